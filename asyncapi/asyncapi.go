@@ -0,0 +1,289 @@
+// Package asyncapi describes the exchanges, queues, headers and payloads of the saga library as an
+// AsyncAPI 3.0 document, with the JSON Schemas of the payloads of the registered events.
+//
+//	doc := asyncapi.Generate(asyncapi.Options{Title: "legend", Version: "1.0.0"})
+//	data, err := doc.YAML()
+//
+// The document is also exported by the saga CLI: go run ./cmd/saga asyncapi.
+package asyncapi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/legendaryum-metaverse/saga/micro"
+)
+
+const (
+	Version        = "3.0.0"
+	bindingVersion = "0.3.0"
+	vhost          = "/"
+)
+
+// Options of the document.
+type Options struct {
+	Title       string
+	Version     string
+	Description string
+	// Host of the RabbitMQ server, e.g. "localhost:5672"; the document has no server when empty.
+	Host string
+}
+
+// auditQueues are the queues of the audit events, published to the AuditExchange instead of the
+// MatchingExchange.
+var auditQueues = map[event.MicroserviceEvent]saga.Queue{
+	event.AuditPublishedEvent:  saga.AuditPublishedCommandsQ,
+	event.AuditReceivedEvent:   saga.AuditReceivedCommandsQ,
+	event.AuditProcessedEvent:  saga.AuditProcessedCommandsQ,
+	event.AuditDeadLetterEvent: saga.AuditDeadLetterCommandsQ,
+	event.AuditReplayedEvent:   saga.AuditReplayedCommandsQ,
+}
+
+// commencePayloads are the payloads of the sagas that can be commenced, see saga.CommenceSaga.
+var commencePayloads = []saga.CommencePayload{
+	saga.TransferCryptoRewardToMissionWinnerPayload{},
+	saga.TransferCryptoRewardToRankingWinnersPayload{},
+}
+
+// Generate describes the events registered in the event package, built-in or registered with
+// event.Register, the saga commands of the microservices and the audit events.
+func Generate(opts Options) *Document {
+	doc := &Document{
+		AsyncAPI: Version,
+		Info: Info{
+			Title:       opts.Title,
+			Version:     opts.Version,
+			Description: opts.Description,
+		},
+		DefaultContentType: saga.ContentTypeJSON,
+		Channels:           map[string]*Channel{},
+		Operations:         map[string]*Operation{},
+		Components: Components{
+			Schemas:  map[string]*Schema{},
+			Messages: map[string]*Message{},
+		},
+	}
+	if opts.Host != "" {
+		doc.Servers = map[string]*Server{
+			"rabbitmq": {Host: opts.Host, Protocol: "amqp"},
+		}
+	}
+	b := builder{doc: doc, schemas: schemas(doc.Components.Schemas)}
+
+	for _, ev := range event.MicroserviceEventValues() {
+		payload, _ := event.Lookup(ev)
+		if queue, ok := auditQueues[ev]; ok {
+			b.auditEvent(ev, payload, queue)
+		} else {
+			b.event(ev, payload)
+		}
+	}
+	b.sagaCommands()
+	b.commenceSaga()
+	return doc
+}
+
+type builder struct {
+	doc     *Document
+	schemas schemas
+}
+
+// message adds the message to the components and to the channel, it returns the reference to the
+// message of the channel used by the operations.
+func (b *builder) message(channel string, msg *Message) Reference {
+	b.doc.Components.Messages[msg.Name] = msg
+	b.doc.Channels[channel].Messages[msg.Name] = &Reference{Ref: "#/components/messages/" + msg.Name}
+	return Reference{Ref: fmt.Sprintf("#/channels/%s/messages/%s", channel, msg.Name)}
+}
+
+func (b *builder) operation(name, action, channel, summary string, binding *AMQPOperationBinding, messages ...Reference) {
+	binding.BindingVersion = bindingVersion
+	b.doc.Operations[name] = &Operation{
+		Action:   action,
+		Channel:  Reference{Ref: "#/channels/" + channel},
+		Summary:  summary,
+		Messages: messages,
+		Bindings: &OperationBindings{AMQP: binding},
+	}
+}
+
+func exchange(name saga.Exchange, kind string) *AMQPExchange {
+	return &AMQPExchange{Name: string(name), Type: kind, Durable: true, Vhost: vhost}
+}
+
+func constant(value string, description string) *Schema {
+	return &Schema{Type: "string", Const: value, Description: description}
+}
+
+// eventHeaders are the headers routing the event in the MatchingExchange, see saga.EventHeaderKey.
+func eventHeaders(ev event.MicroserviceEvent) *Schema {
+	key := saga.EventHeaderKey(ev)
+	return &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			key:         constant(string(ev), "Routes the message to the exchange of the event."),
+			"all-micro": constant("yes", "Set when publishing, routes the event to every microservice subscribed to it."),
+			"micro": {
+				Type:        "string",
+				Description: "Set instead of all-micro when the event is requeued with saga.RequeueExpiration, the queue of the microservice that nacked it.",
+			},
+			"x-retry-count": {Type: "integer", Description: "Times the event was nacked with delay, see docs/headers.md."},
+			"x-occurrence":  {Type: "integer", Description: "Occurrence of the fibonacci nack strategy."},
+		},
+		Required: []string{key},
+	}
+}
+
+func (b *builder) event(ev event.MicroserviceEvent, payload reflect.Type) {
+	name := string(ev)
+	b.doc.Channels[name] = &Channel{
+		Address: name,
+		Title:   name,
+		Description: fmt.Sprintf(
+			"The event is published to the %[1]s headers exchange with the headers %[2]s=%[3]s and all-micro=yes "+
+				"(x-match=all), which routes it through the %[3]s exchange to the queue of every microservice subscribed to it. "+
+				"A nacked event waits in a <microservice>_match_commands_delay_<ms> queue, which dead-letters it "+
+				"through the default exchange to the microservice that nacked it only, see saga.RequeueStrategy.",
+			saga.MatchingExchange, saga.EventHeaderKey(ev), ev,
+		),
+		Messages: map[string]*Reference{},
+		Bindings: &ChannelBindings{AMQP: &AMQPChannelBinding{
+			Is:             "routingKey",
+			Exchange:       exchange(saga.MatchingExchange, "headers"),
+			BindingVersion: bindingVersion,
+		}},
+	}
+	msg := b.message(name, &Message{
+		Name:        name,
+		Title:       payload.Name(),
+		ContentType: saga.ContentTypeJSON,
+		Headers:     eventHeaders(ev),
+		Payload:     b.schemas.of(payload, nil),
+		Bindings: &MessageBindings{AMQP: &AMQPMessageBinding{
+			MessageType:    name,
+			BindingVersion: bindingVersion,
+		}},
+	})
+	b.operation("publish."+name, "send", name, "Publish the "+name+" event, see saga.Transactional.PublishEvent.",
+		&AMQPOperationBinding{DeliveryMode: 2, Mandatory: true}, msg)
+	b.operation("consume."+name, "receive", name, "Consume the "+name+" event, see saga.Opts.Events.",
+		&AMQPOperationBinding{Ack: true}, msg)
+}
+
+func (b *builder) auditEvent(ev event.MicroserviceEvent, payload reflect.Type, queue saga.Queue) {
+	name := string(ev)
+	b.doc.Channels[name] = &Channel{
+		Address: name,
+		Title:   name,
+		Description: fmt.Sprintf("The audit event is published to the %s direct exchange with the routing key %s, "+
+			"bound to the %s queue consumed by the audit microservice.", saga.AuditExchange, ev, queue),
+		Messages: map[string]*Reference{},
+		Bindings: &ChannelBindings{AMQP: &AMQPChannelBinding{
+			Is:             "routingKey",
+			Exchange:       exchange(saga.AuditExchange, "direct"),
+			BindingVersion: bindingVersion,
+		}},
+	}
+	msg := b.message(name, &Message{
+		Name:        name,
+		Title:       payload.Name(),
+		ContentType: saga.ContentTypeJSON,
+		Payload:     b.schemas.of(payload, nil),
+		Bindings: &MessageBindings{AMQP: &AMQPMessageBinding{
+			MessageType:    name,
+			BindingVersion: bindingVersion,
+		}},
+	})
+	b.operation("publish."+name, "send", name, "Published by every microservice, see saga.Transactional.PublishAuditEvent.",
+		&AMQPOperationBinding{DeliveryMode: 2}, msg)
+	b.operation("consume."+name, "receive", name, "Consumed from the "+string(queue)+" queue by the audit microservice.",
+		&AMQPOperationBinding{Ack: true}, msg)
+}
+
+func (b *builder) sagaCommands() {
+	const commands, reply = "saga_commands", string(saga.ReplyToSagaQ)
+	var names []string
+	for _, m := range micro.Microservices() {
+		names = append(names, string(m))
+	}
+
+	b.doc.Channels[commands] = &Channel{
+		Address: "{microservice}_routing_key",
+		Title:   "Saga commands",
+		Description: fmt.Sprintf("The saga steps are published by the transactional microservice to the %s direct "+
+			"exchange, bound to the queue of the microservice, named after it. A nacked step waits in a "+
+			"<microservice>_saga_commands_delay_<ms> queue, which dead-letters it through the default exchange "+
+			"to the queue, see saga.RequeueStrategy.", saga.CommandsExchange),
+		Messages: map[string]*Reference{},
+		Parameters: map[string]*Parameter{
+			"microservice": {Description: "The microservice of the step.", Enum: names},
+		},
+		Bindings: &ChannelBindings{AMQP: &AMQPChannelBinding{
+			Is:             "routingKey",
+			Exchange:       exchange(saga.CommandsExchange, "direct"),
+			BindingVersion: bindingVersion,
+		}},
+	}
+	b.doc.Channels[reply] = &Channel{
+		Address:     reply,
+		Title:       "Saga step replies",
+		Description: "The microservices reply the result of the saga steps to the transactional microservice.",
+		Messages:    map[string]*Reference{},
+		Bindings: &ChannelBindings{AMQP: &AMQPChannelBinding{
+			Is:             "queue",
+			Queue:          &AMQPQueue{Name: reply, Durable: true, Vhost: vhost},
+			BindingVersion: bindingVersion,
+		}},
+	}
+
+	step := b.schemas.of(reflect.TypeFor[saga.SagaStep](), nil)
+	msg := b.message(commands, &Message{
+		Name:        "saga_step",
+		Title:       "SagaStep",
+		ContentType: saga.ContentTypeJSON,
+		Payload:     step,
+	})
+	b.operation("consume.saga_commands", "receive", commands,
+		"Consume the saga steps of the microservice, see saga.Transactional.ConnectSagaCommands.", &AMQPOperationBinding{Ack: true}, msg)
+
+	b.doc.Channels[reply].Messages["saga_step"] = &Reference{Ref: "#/components/messages/saga_step"}
+	b.operation("reply.saga_step", "send", reply, "Reply the result of a saga step, see saga.MicroserviceConsumeChannel.AckMessage.",
+		&AMQPOperationBinding{DeliveryMode: 2, Mandatory: true},
+		Reference{Ref: fmt.Sprintf("#/channels/%s/messages/saga_step", reply)})
+}
+
+func (b *builder) commenceSaga() {
+	name := string(saga.CommenceSagaQueue)
+	b.doc.Channels[name] = &Channel{
+		Address:     name,
+		Title:       "Commence saga",
+		Description: "Asks the transactional microservice to commence a saga.",
+		Messages:    map[string]*Reference{},
+		Bindings: &ChannelBindings{AMQP: &AMQPChannelBinding{
+			Is:             "queue",
+			Queue:          &AMQPQueue{Name: name, Durable: true, Vhost: vhost},
+			BindingVersion: bindingVersion,
+		}},
+	}
+	messages := make([]Reference, 0, len(commencePayloads))
+	for _, payload := range commencePayloads {
+		title := string(payload.Type())
+		messages = append(messages, b.message(name, &Message{
+			Name:        title,
+			Title:       reflect.TypeOf(payload).Name(),
+			ContentType: saga.ContentTypeJSON,
+			Payload: &Schema{
+				Type: "object",
+				Properties: map[string]*Schema{
+					"title":   constant(title, "The saga to commence."),
+					"payload": b.schemas.of(reflect.TypeOf(payload), nil),
+				},
+				Required: []string{"title", "payload"},
+			},
+		}))
+	}
+	b.operation("commence_saga", "send", name, "Commence a saga, see saga.Transactional.CommenceSaga.",
+		&AMQPOperationBinding{DeliveryMode: 2, Mandatory: true}, messages...)
+}
@@ -0,0 +1,162 @@
+package asyncapi
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is an AsyncAPI 3.0 document, only the objects used to describe the saga library are defined.
+type Document struct {
+	AsyncAPI           string                `json:"asyncapi"`
+	Info               Info                  `json:"info"`
+	Servers            map[string]*Server    `json:"servers,omitempty"`
+	DefaultContentType string                `json:"defaultContentType"`
+	Channels           map[string]*Channel   `json:"channels"`
+	Operations         map[string]*Operation `json:"operations"`
+	Components         Components            `json:"components"`
+}
+
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+type Server struct {
+	Host        string `json:"host"`
+	Protocol    string `json:"protocol"`
+	Description string `json:"description,omitempty"`
+}
+
+type Channel struct {
+	Address     string                `json:"address"`
+	Title       string                `json:"title,omitempty"`
+	Description string                `json:"description,omitempty"`
+	Messages    map[string]*Reference `json:"messages"`
+	Parameters  map[string]*Parameter `json:"parameters,omitempty"`
+	Bindings    *ChannelBindings      `json:"bindings,omitempty"`
+}
+
+type Parameter struct {
+	Description string   `json:"description,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+}
+
+type ChannelBindings struct {
+	AMQP *AMQPChannelBinding `json:"amqp"`
+}
+
+// AMQPChannelBinding is the AMQP channel binding 0.3.0, Is is "routingKey" when the address is a
+// routing key of the Exchange or "queue" when it is the Queue.
+type AMQPChannelBinding struct {
+	Is             string        `json:"is"`
+	Exchange       *AMQPExchange `json:"exchange,omitempty"`
+	Queue          *AMQPQueue    `json:"queue,omitempty"`
+	BindingVersion string        `json:"bindingVersion"`
+}
+
+type AMQPExchange struct {
+	Name       string `json:"name"`
+	Type       string `json:"type"`
+	Durable    bool   `json:"durable"`
+	AutoDelete bool   `json:"autoDelete"`
+	Vhost      string `json:"vhost"`
+}
+
+type AMQPQueue struct {
+	Name       string `json:"name"`
+	Durable    bool   `json:"durable"`
+	Exclusive  bool   `json:"exclusive"`
+	AutoDelete bool   `json:"autoDelete"`
+	Vhost      string `json:"vhost"`
+}
+
+type Operation struct {
+	// Action is "send" or "receive".
+	Action   string             `json:"action"`
+	Channel  Reference          `json:"channel"`
+	Summary  string             `json:"summary,omitempty"`
+	Messages []Reference        `json:"messages"`
+	Bindings *OperationBindings `json:"bindings,omitempty"`
+}
+
+type OperationBindings struct {
+	AMQP *AMQPOperationBinding `json:"amqp"`
+}
+
+type AMQPOperationBinding struct {
+	DeliveryMode   int    `json:"deliveryMode,omitempty"`
+	Mandatory      bool   `json:"mandatory,omitempty"`
+	Ack            bool   `json:"ack,omitempty"`
+	BindingVersion string `json:"bindingVersion"`
+}
+
+type Message struct {
+	Name        string           `json:"name"`
+	Title       string           `json:"title,omitempty"`
+	Summary     string           `json:"summary,omitempty"`
+	ContentType string           `json:"contentType,omitempty"`
+	Headers     *Schema          `json:"headers,omitempty"`
+	Payload     *Schema          `json:"payload"`
+	Bindings    *MessageBindings `json:"bindings,omitempty"`
+}
+
+type MessageBindings struct {
+	AMQP *AMQPMessageBinding `json:"amqp"`
+}
+
+type AMQPMessageBinding struct {
+	ContentEncoding string `json:"contentEncoding,omitempty"`
+	MessageType     string `json:"messageType,omitempty"`
+	BindingVersion  string `json:"bindingVersion"`
+}
+
+type Components struct {
+	Schemas  map[string]*Schema  `json:"schemas"`
+	Messages map[string]*Message `json:"messages"`
+}
+
+// Reference is a reference to an object of the document, e.g. "#/components/messages/social.new_user".
+type Reference struct {
+	Ref string `json:"$ref"`
+}
+
+// JSON encodes the document as indented JSON.
+func (d *Document) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// YAML encodes the document as YAML, with the same key order as JSON.
+func (d *Document) YAML() ([]byte, error) {
+	data, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	// JSON is YAML, decoding it to a node keeps the order of the keys
+	var node yaml.Node
+	if err = yaml.Unmarshal(data, &node); err != nil {
+		return nil, err
+	}
+	resetStyle(&node)
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err = enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err = enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// resetStyle drops the flow style and quotes of the JSON, the strings that would be read as another
+// type are still quoted.
+func resetStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, n := range node.Content {
+		resetStyle(n)
+	}
+}
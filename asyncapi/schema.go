@@ -0,0 +1,223 @@
+package asyncapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema is a JSON Schema (draft 07), the default schema format of AsyncAPI.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Const                any                `json:"const,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// schemas builds the JSON Schemas of Go types, the named structs are added once to the components
+// and referenced.
+type schemas map[string]*Schema
+
+// ref returns a reference to the schema of the named struct t, adding it to the components.
+func (s schemas) ref(t reflect.Type) *Schema {
+	name := t.Name()
+	if _, ok := s[name]; !ok {
+		// reserved before walking the fields, the types can be recursive
+		s[name] = nil
+		s[name] = s.object(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// object is the schema of the fields of the struct t, their names and the required ones are taken
+// from the json and validate tags.
+func (s schemas) object(t reflect.Type) *Schema {
+	schema := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" && opts == "" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		rules := strings.Split(f.Tag.Get("validate"), ",")
+		field := s.of(f.Type, rules)
+		if f.Type.Kind() == reflect.Pointer && !strings.Contains(opts, "omitempty") {
+			// a nil pointer is encoded as null
+			field = nullable(field)
+		}
+		schema.Properties[name] = field
+		for _, rule := range rules {
+			if rule == "required" {
+				schema.Required = append(schema.Required, name)
+			}
+		}
+	}
+	return schema
+}
+
+// of returns the schema of t with the validation rules applied.
+func (s schemas) of(t reflect.Type, rules []string) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	var schema *Schema
+	switch {
+	case t == timeType:
+		schema = &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.Struct && t.Name() != "":
+		return s.ref(t)
+	case t.Kind() == reflect.Struct:
+		schema = s.object(t)
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			// []byte is encoded as base64
+			schema = &Schema{Type: "string", Format: "byte"}
+			break
+		}
+		var items []string
+		rules, items = splitDive(rules)
+		schema = &Schema{Type: "array", Items: s.of(t.Elem(), items)}
+	case t.Kind() == reflect.Map:
+		var values []string
+		rules, values = splitDive(rules)
+		schema = &Schema{Type: "object", AdditionalProperties: s.of(t.Elem(), values)}
+	case t.Kind() == reflect.Interface:
+		// any value
+		schema = &Schema{}
+	default:
+		schema = &Schema{Type: primitive(t.Kind())}
+	}
+	applyRules(schema, rules)
+	return schema
+}
+
+func primitive(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	default:
+		return "string"
+	}
+}
+
+func nullable(schema *Schema) *Schema {
+	if t, ok := schema.Type.(string); ok && schema.Ref == "" {
+		schema.Type = []string{t, "null"}
+		return schema
+	}
+	return &Schema{AnyOf: []*Schema{schema, {Type: "null"}}}
+}
+
+// splitDive splits the rules of a list or map from the ones of its elements.
+func splitDive(rules []string) ([]string, []string) {
+	for i, rule := range rules {
+		if rule == "dive" {
+			return rules[:i], rules[i+1:]
+		}
+	}
+	return rules, nil
+}
+
+// applyRules translates the validation rules that have a JSON Schema equivalent.
+func applyRules(schema *Schema, rules []string) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+		switch name {
+		case "email":
+			schema.Format = "email"
+		case "url", "uri":
+			schema.Format = "uri"
+		case "uuid", "uuid4", "uuid7":
+			schema.Format = "uuid"
+		case "datetime":
+			schema.Format = "date-time"
+		case "oneof":
+			for _, v := range strings.Fields(param) {
+				schema.Enum = append(schema.Enum, enumValue(schema.Type, v))
+			}
+		case "len", "min", "max", "gte", "lte", "gt", "lt":
+			n, err := strconv.ParseFloat(param, 64)
+			if err != nil {
+				continue
+			}
+			applyBound(schema, name, n)
+		}
+	}
+}
+
+func enumValue(t any, v string) any {
+	if t == "integer" || t == "number" {
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	}
+	return v
+}
+
+// applyBound applies a length bound to strings and lists and a value bound to numbers.
+func applyBound(schema *Schema, rule string, n float64) {
+	switch schema.Type {
+	case "string", "array":
+		length := int(n)
+		lower, upper := &schema.MinLength, &schema.MaxLength
+		if schema.Type == "array" {
+			lower, upper = &schema.MinItems, &schema.MaxItems
+		}
+		switch rule {
+		case "len":
+			*lower, *upper = &length, &length
+		case "min", "gte":
+			*lower = &length
+		case "max", "lte":
+			*upper = &length
+		case "gt":
+			length++
+			*lower = &length
+		case "lt":
+			length--
+			*upper = &length
+		}
+	case "integer", "number":
+		switch rule {
+		case "len":
+			schema.Minimum, schema.Maximum = &n, &n
+		case "min", "gte":
+			schema.Minimum = &n
+		case "max", "lte":
+			schema.Maximum = &n
+		case "gt":
+			schema.ExclusiveMinimum = &n
+		case "lt":
+			schema.ExclusiveMaximum = &n
+		}
+	}
+}
@@ -0,0 +1,214 @@
+// Package catalog describes the events, payloads, microservices and saga commands shared by the
+// saga libraries. The catalog is a YAML (or JSON) document, the Go event and micro packages are
+// generated from it with cmd/saga-gen.
+package catalog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Catalog is the root of the catalog document.
+type Catalog struct {
+	Microservices []Microservice `yaml:"microservices"`
+	Events        []Event        `yaml:"events"`
+	// EventTypes are the types shared by the event payloads, generated in the event package.
+	EventTypes []Type `yaml:"eventTypes"`
+	// CommandTypes are the payloads and results of the saga commands, generated in the micro package.
+	CommandTypes []Type `yaml:"commandTypes"`
+}
+
+// Microservice is a microservice and the saga commands it handles.
+type Microservice struct {
+	// Const is the name of the Go constant, e.g. "Auth".
+	Const    string    `yaml:"const"`
+	Name     string    `yaml:"name"`
+	Doc      string    `yaml:"doc"`
+	Commands []Command `yaml:"commands"`
+}
+
+// Command is a saga command.
+type Command struct {
+	Const string `yaml:"const"`
+	Name  string `yaml:"name"`
+	Doc   string `yaml:"doc"`
+	// Binding is the name of the typed binding of the command, see micro.RegisterCommand.
+	Binding string `yaml:"binding"`
+	// Payload and Result are CommandTypes or one of the builtins Untyped and NoResult.
+	Payload string `yaml:"payload"`
+	Result  string `yaml:"result"`
+}
+
+// Event is an event and its payload.
+type Event struct {
+	Const string `yaml:"const"`
+	Name  string `yaml:"name"`
+	Doc   string `yaml:"doc"`
+	// Group is a comment written before the first of the consecutive events of the same group.
+	Group   string `yaml:"group"`
+	Payload Type   `yaml:"payload"`
+}
+
+// Type is a named type: a struct when it has Fields, a string enum when it has Enum, or a map of
+// string keys when it has Map.
+type Type struct {
+	Name   string  `yaml:"name"`
+	Doc    string  `yaml:"doc"`
+	Fields []Field `yaml:"fields"`
+	Enum   []Value `yaml:"enum"`
+	// Map is the type of the values of a map type.
+	Map string `yaml:"map"`
+}
+
+// Value is a value of an enum.
+type Value struct {
+	Const string `yaml:"const"`
+	Value string `yaml:"value"`
+}
+
+// Field is a field of a struct.
+type Field struct {
+	Name string `yaml:"name"`
+	JSON string `yaml:"json"`
+	// Type is one of the primitives (string, int, int64, uint32, uint64, float32, float64, bool,
+	// datetime, any) or the name of a type of the catalog.
+	Type string `yaml:"type"`
+	// List makes the field a list of Type, Map a map of string keys to Type.
+	List bool `yaml:"list"`
+	Map  bool `yaml:"map"`
+	// Nullable makes the field optional, a pointer in Go.
+	Nullable  bool `yaml:"nullable"`
+	OmitEmpty bool `yaml:"omitempty"`
+	// BSON adds a bson tag equal to the json one.
+	BSON     bool   `yaml:"bson"`
+	Validate string `yaml:"validate"`
+	Doc      string `yaml:"doc"`
+	// Comment is written at the end of the line of the field.
+	Comment string `yaml:"comment"`
+}
+
+// Primitives are the field types that are not defined in the catalog.
+var Primitives = map[string]bool{
+	"string": true, "int": true, "int64": true, "uint32": true, "uint64": true,
+	"float32": true, "float64": true, "bool": true, "datetime": true, "any": true,
+}
+
+// Builtin command payloads and results, defined by hand in the micro package.
+var builtinCommandTypes = map[string]bool{"Untyped": true, "NoResult": true}
+
+// Load reads and validates the catalog, unknown keys are an error.
+func Load(path string) (*Catalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse decodes and validates the catalog document.
+func Parse(data []byte) (*Catalog, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var c Catalog
+	if err := dec.Decode(&c); err != nil {
+		return nil, fmt.Errorf("invalid catalog: %w", err)
+	}
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Validate checks that the names are unique and that every referenced type is defined.
+func (c *Catalog) Validate() error {
+	var errs []error
+	unique := func(kind, name string, seen map[string]bool) {
+		if name == "" {
+			errs = append(errs, fmt.Errorf("%s with an empty name", kind))
+		} else if seen[name] {
+			errs = append(errs, fmt.Errorf("duplicated %s %q", kind, name))
+		}
+		seen[name] = true
+	}
+
+	consts, names := map[string]bool{}, map[string]bool{}
+	commandConsts, commandNames, bindings := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	commandTypes := typeNames(c.CommandTypes)
+	for _, m := range c.Microservices {
+		unique("microservice const", m.Const, consts)
+		unique("microservice", m.Name, names)
+		for _, cmd := range m.Commands {
+			unique("command const", cmd.Const, commandConsts)
+			unique("command", cmd.Name, commandNames)
+			unique("command binding", cmd.Binding, bindings)
+			for _, t := range []string{cmd.Payload, cmd.Result} {
+				if !commandTypes[t] && !builtinCommandTypes[t] {
+					errs = append(errs, fmt.Errorf("command %s: undefined type %q", cmd.Name, t))
+				}
+			}
+		}
+	}
+
+	eventConsts, eventNames, payloads := map[string]bool{}, map[string]bool{}, map[string]bool{}
+	eventTypes := typeNames(c.EventTypes)
+	for _, ev := range c.Events {
+		unique("event const", ev.Const, eventConsts)
+		unique("event", ev.Name, eventNames)
+		unique("payload", ev.Payload.Name, payloads)
+		if eventTypes[ev.Payload.Name] {
+			errs = append(errs, fmt.Errorf("payload %s is also an event type", ev.Payload.Name))
+		}
+		errs = append(errs, checkFields(ev.Payload, eventTypes)...)
+	}
+	seen := map[string]bool{}
+	for _, t := range c.EventTypes {
+		unique("event type", t.Name, seen)
+		errs = append(errs, checkFields(t, eventTypes)...)
+	}
+	seen = map[string]bool{}
+	for _, t := range c.CommandTypes {
+		unique("command type", t.Name, seen)
+		errs = append(errs, checkFields(t, commandTypes)...)
+	}
+	return errors.Join(errs...)
+}
+
+func typeNames(types []Type) map[string]bool {
+	names := make(map[string]bool, len(types))
+	for _, t := range types {
+		names[t.Name] = true
+	}
+	return names
+}
+
+func checkFields(t Type, defined map[string]bool) []error {
+	var errs []error
+	kinds := 0
+	for _, set := range []bool{len(t.Fields) > 0, len(t.Enum) > 0, t.Map != ""} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		errs = append(errs, fmt.Errorf("type %s: only one of fields, enum and map can be set", t.Name))
+	}
+	if t.Map != "" && !Primitives[t.Map] && !defined[t.Map] {
+		errs = append(errs, fmt.Errorf("type %s: undefined map type %q", t.Name, t.Map))
+	}
+	for _, f := range t.Fields {
+		if f.Name == "" || f.JSON == "" {
+			errs = append(errs, fmt.Errorf("type %s: field without name or json name", t.Name))
+		}
+		if !Primitives[f.Type] && !defined[f.Type] {
+			errs = append(errs, fmt.Errorf("type %s: field %s has an undefined type %q", t.Name, f.Name, f.Type))
+		}
+		if f.List && f.Map {
+			errs = append(errs, fmt.Errorf("type %s: field %s cannot be both a list and a map", t.Name, f.Name))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"go/format"
+	"strings"
+
+	"github.com/legendaryum-metaverse/saga/catalog"
+)
+
+const header = "// Code generated by saga-gen from %s. DO NOT EDIT.\n\n"
+
+// goTypes maps the primitives of the catalog to Go.
+var goTypes = map[string]string{
+	"string":   "string",
+	"int":      "int",
+	"int64":    "int64",
+	"uint32":   "uint32",
+	"uint64":   "uint64",
+	"float32":  "float32",
+	"float64":  "float64",
+	"bool":     "bool",
+	"datetime": "time.Time",
+	"any":      "interface{}",
+}
+
+// generator writes a Go source file, the result is formatted with gofmt.
+type generator struct {
+	b strings.Builder
+}
+
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.b, format, args...)
+}
+
+// comment writes the doc as line comments with the given indentation.
+func (g *generator) comment(doc, indent string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(doc, "\n"), "\n") {
+		g.printf("%s// %s\n", indent, line)
+	}
+}
+
+func (g *generator) format() ([]byte, error) {
+	return format.Source([]byte(g.b.String()))
+}
+
+func goType(f catalog.Field) string {
+	t, ok := goTypes[f.Type]
+	if !ok {
+		t = f.Type
+	}
+	switch {
+	case f.List:
+		t = "[]" + t
+	case f.Map:
+		t = "map[string]" + t
+	}
+	if f.Nullable {
+		t = "*" + t
+	}
+	return t
+}
+
+func tags(f catalog.Field) string {
+	name := f.JSON
+	if f.OmitEmpty {
+		name += ",omitempty"
+	}
+	tags := []string{fmt.Sprintf("json:%q", name)}
+	if f.BSON {
+		tags = append(tags, fmt.Sprintf("bson:%q", name))
+	}
+	if f.Validate != "" {
+		tags = append(tags, fmt.Sprintf("validate:%q", f.Validate))
+	}
+	return strings.Join(tags, " ")
+}
+
+func (g *generator) typeDecl(t catalog.Type) {
+	g.comment(t.Doc, "")
+	switch {
+	case len(t.Enum) > 0:
+		g.printf("type %s string\n\nconst (\n", t.Name)
+		for _, v := range t.Enum {
+			g.printf("\t%s %s = %q\n", v.Const, t.Name, v.Value)
+		}
+		g.printf(")\n\n")
+	case t.Map != "":
+		g.printf("type %s %s\n\n", t.Name, goType(catalog.Field{Type: t.Map, Map: true}))
+	default:
+		g.printf("type %s struct {\n", t.Name)
+		for _, f := range t.Fields {
+			g.comment(f.Doc, "\t")
+			g.printf("\t%s %s `%s`", f.Name, goType(f), tags(f))
+			if f.Comment != "" {
+				g.printf(" // %s", f.Comment)
+			}
+			g.printf("\n")
+		}
+		g.printf("}\n\n")
+	}
+}
+
+func usesTime(types ...[]catalog.Type) bool {
+	for _, ts := range types {
+		for _, t := range ts {
+			for _, f := range t.Fields {
+				if f.Type == "datetime" {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// generateEvents generates the event constants, the payloads with their Type method and the
+// registration of the built-in events.
+func generateEvents(c *catalog.Catalog, source string) ([]byte, error) {
+	var g generator
+	g.printf(header, source)
+	g.printf("package event\n\n")
+
+	payloads := make([]catalog.Type, len(c.Events))
+	for i, ev := range c.Events {
+		payloads[i] = ev.Payload
+	}
+	if usesTime(c.EventTypes, payloads) {
+		g.printf("import \"time\"\n\n")
+	}
+
+	g.printf("const (\n")
+	group := ""
+	for i, ev := range c.Events {
+		if ev.Group != group {
+			if i > 0 {
+				g.printf("\n")
+			}
+			g.comment(ev.Group, "\t")
+			group = ev.Group
+		}
+		g.comment(ev.Doc, "\t")
+		g.printf("\t%s MicroserviceEvent = %q\n", ev.Const, ev.Name)
+	}
+	g.printf(")\n\n")
+
+	for _, t := range c.EventTypes {
+		g.typeDecl(t)
+	}
+	for _, ev := range c.Events {
+		g.typeDecl(ev.Payload)
+		g.printf("func (%s) Type() MicroserviceEvent {\n\treturn %s\n}\n\n", ev.Payload.Name, ev.Const)
+	}
+
+	g.printf("func init() {\n\tRegister(\n")
+	for _, ev := range c.Events {
+		g.printf("\t\t%s{},\n", ev.Payload.Name)
+	}
+	g.printf("\t)\n}\n")
+	return g.format()
+}
+
+// generateMicro generates the microservices and saga commands constants, the command payloads, their
+// typed bindings and the registration of the built-in microservices.
+func generateMicro(c *catalog.Catalog, source string) ([]byte, error) {
+	var g generator
+	g.printf(header, source)
+	g.printf("package micro\n\n")
+	if usesTime(c.CommandTypes) {
+		g.printf("import \"time\"\n\n")
+	}
+
+	for _, m := range c.Microservices {
+		g.comment(m.Doc, "")
+		g.printf("const (\n\t%s AvailableMicroservices = %q\n", m.Const, m.Name)
+		for _, cmd := range m.Commands {
+			g.comment(cmd.Doc, "\t")
+			g.printf("\t%s StepCommand = %q\n", cmd.Const, cmd.Name)
+		}
+		g.printf(")\n\n")
+	}
+
+	for _, t := range c.CommandTypes {
+		g.typeDecl(t)
+	}
+
+	g.printf("// Typed bindings of the built-in commands.\nvar (\n")
+	for _, m := range c.Microservices {
+		for _, cmd := range m.Commands {
+			g.printf("\t%s = RegisterCommand[%s, %s](%s)\n", cmd.Binding, cmd.Payload, cmd.Result, cmd.Const)
+		}
+	}
+	g.printf(")\n\n")
+
+	g.printf("func init() {\n\tRegister(\n")
+	for _, m := range c.Microservices {
+		g.printf("\t\t%s,\n", m.Const)
+	}
+	g.printf("\t)\n}\n")
+	return g.format()
+}
@@ -0,0 +1,68 @@
+// Command saga-gen generates the Go event and micro packages from the catalog of events, payloads,
+// microservices and saga commands.
+//
+//	go run ./cmd/saga-gen                # regenerate event/events_gen.go and micro/micro_gen.go
+//	go run ./cmd/saga-gen -check         # fail if the generated files are stale
+//
+// The catalog is a YAML or JSON document, see the catalog package.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/legendaryum-metaverse/saga/catalog"
+)
+
+func main() {
+	catalogPath := flag.String("catalog", "catalog/catalog.yaml", "path of the catalog")
+	root := flag.String("root", ".", "root of the saga module, where the event and micro packages are")
+	check := flag.Bool("check", false, "do not write the files, fail if they are not up to date")
+	flag.Parse()
+	log.SetFlags(0)
+	log.SetPrefix("saga-gen: ")
+
+	c, err := catalog.Load(*catalogPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	source := filepath.ToSlash(*catalogPath)
+	if rel, relErr := filepath.Rel(*root, *catalogPath); relErr == nil {
+		source = filepath.ToSlash(rel)
+	}
+
+	files := []struct {
+		path     string
+		generate func(*catalog.Catalog, string) ([]byte, error)
+	}{
+		{filepath.Join(*root, "event", "events_gen.go"), generateEvents},
+		{filepath.Join(*root, "micro", "micro_gen.go"), generateMicro},
+	}
+
+	stale := 0
+	for _, f := range files {
+		content, err := f.generate(c, source)
+		if err != nil {
+			log.Fatalf("generating %s: %v", f.path, err)
+		}
+		if *check {
+			current, err := os.ReadFile(f.path)
+			if err != nil || !bytes.Equal(current, content) {
+				fmt.Fprintf(os.Stderr, "%s is stale, run saga-gen\n", f.path)
+				stale++
+			}
+			continue
+		}
+		if err = os.WriteFile(f.path, content, 0o644); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if stale > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,46 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/legendaryum-metaverse/saga/asyncapi"
+)
+
+func runAsyncAPI(args []string) error {
+	flags := flag.NewFlagSet("asyncapi", flag.ExitOnError)
+	format := flags.String("format", "yaml", "format of the document, yaml or json")
+	output := flags.String("o", "", "file to write the document to, stdout when empty")
+	title := flags.String("title", "saga", "title of the document")
+	version := flags.String("version", "1.0.0", "version of the document")
+	description := flags.String("description", "", "description of the document")
+	host := flags.String("host", "", "host of the RabbitMQ server, e.g. localhost:5672")
+	_ = flags.Parse(args)
+
+	doc := asyncapi.Generate(asyncapi.Options{
+		Title:       *title,
+		Version:     *version,
+		Description: *description,
+		Host:        *host,
+	})
+	var data []byte
+	var err error
+	switch *format {
+	case "yaml":
+		data, err = doc.YAML()
+	case "json":
+		data, err = doc.JSON()
+		data = append(data, '\n')
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(*output, data, 0o644)
+}
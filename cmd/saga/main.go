@@ -0,0 +1,45 @@
+// Command saga is the command line tool of the saga library.
+//
+//	saga asyncapi [-format yaml|json] [-o file]   export the AsyncAPI document of the events and saga commands
+//	saga parking-lot list|show|replay|purge -micro <microservice> [filters]
+//	                                             inspect, replay and purge the parked messages
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+type command struct {
+	usage string
+	run   func(args []string) error
+}
+
+var commands = map[string]command{
+	"asyncapi":    {"export the AsyncAPI document of the events and saga commands", runAsyncAPI},
+	"parking-lot": {"inspect, replay and purge the parked messages", runParkingLot},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: saga <command> [flags]\n\ncommands:")
+	for _, name := range []string{"asyncapi", "parking-lot"} {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", name, commands[name].usage)
+	}
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "saga: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "saga %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/micro"
+)
+
+const parkingLotUsage = `usage: saga parking-lot <list|show|replay|purge> -micro <microservice> [flags]
+
+  list      list the parked messages selected by the filters
+  show      show the parked message -id
+  replay    replay the selected messages to the microservice that parked them
+  purge     remove the selected messages
+
+replay and purge need a filter or -all.`
+
+func runParkingLot(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, parkingLotUsage)
+		os.Exit(2)
+	}
+	action := args[0]
+	flags := flag.NewFlagSet("parking-lot "+action, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(os.Stderr, parkingLotUsage+"\n\nflags:")
+		flags.PrintDefaults()
+	}
+	uri := flags.String("uri", os.Getenv("RABBIT_URI"), "uri of RabbitMQ, $RABBIT_URI by default")
+	microservice := flags.String("micro", "", "microservice owning the parking lot queues")
+	handler := flags.String("handler", "", "event or saga command of the messages")
+	appID := flags.String("app", "", "publisher microservice of the messages")
+	since := flags.String("since", "", "messages parked since the time, RFC 3339 or a duration ago like 24h")
+	until := flags.String("until", "", "messages parked until the time, RFC 3339 or a duration ago like 1h")
+	errorText := flags.String("error", "", "substring of the last error or of the parking reason")
+	ids := flags.String("id", "", "comma separated ids of the messages")
+	limit := flags.Int("limit", 0, "maximum number of messages, unlimited if zero")
+	all := flags.Bool("all", false, "select every message for replay and purge")
+	asJSON := flags.Bool("json", false, "print the messages as JSON")
+	_ = flags.Parse(args[1:])
+
+	if *microservice == "" {
+		return errors.New("-micro is required")
+	}
+	filter := saga.ParkedFilter{Handler: *handler, AppID: *appID, Error: *errorText, Limit: *limit}
+	if *ids != "" {
+		filter.MessageIDs = strings.Split(*ids, ",")
+	}
+	var err error
+	if filter.Since, err = parseTime(*since); err != nil {
+		return fmt.Errorf("invalid -since: %w", err)
+	}
+	if filter.Until, err = parseTime(*until); err != nil {
+		return fmt.Errorf("invalid -until: %w", err)
+	}
+
+	switch action {
+	case "list", "show", "replay", "purge":
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+	if action == "show" && len(filter.MessageIDs) != 1 {
+		return errors.New("show needs one -id")
+	}
+	if (action == "replay" || action == "purge") && !*all && isZero(filter) {
+		return fmt.Errorf("%s needs a filter or -all", action)
+	}
+
+	// the microservice may be one that is not built into the library
+	micro.Register(micro.AvailableMicroservices(*microservice))
+	t, err := saga.NewTransactional(&saga.Opts{
+		RabbitUri:    *uri,
+		Microservice: micro.AvailableMicroservices(*microservice),
+	})
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_ = t.Shutdown(ctx)
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	switch action {
+	case "list":
+		messages, err := t.ParkedMessages(ctx, filter)
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(messages)
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tQUEUE\tHANDLER\tAPP\tPARKED AT\tATTEMPTS\tREASON\tLAST ERROR")
+		for _, m := range messages {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%d\t%s\t%s\n", m.MessageID, m.Queue, m.Handler, m.AppID,
+				m.ParkedAt.Format(time.RFC3339), m.Attempts, m.Reason, m.LastError)
+		}
+		return w.Flush()
+	case "show":
+		m, err := t.ParkedMessage(ctx, filter.MessageIDs[0])
+		if err != nil {
+			return err
+		}
+		if *asJSON {
+			return printJSON(m)
+		}
+		printMessage(m)
+		return nil
+	case "replay":
+		n, err := t.ReplayParked(ctx, filter)
+		fmt.Printf("%d messages replayed\n", n)
+		return err
+	default:
+		n, err := t.PurgeParked(ctx, filter)
+		fmt.Printf("%d messages purged\n", n)
+		return err
+	}
+}
+
+func isZero(f saga.ParkedFilter) bool {
+	return f.Handler == "" && f.AppID == "" && f.Error == "" && len(f.MessageIDs) == 0 &&
+		f.Since.IsZero() && f.Until.IsZero()
+}
+
+// parseTime parses an RFC 3339 time or a duration before now.
+func parseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if d, err := time.ParseDuration(value); err == nil {
+		return time.Now().Add(-d), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printMessage(m saga.ParkedMessage) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\t%s\n", m.MessageID)
+	fmt.Fprintf(w, "Queue\t%s\n", m.Queue)
+	fmt.Fprintf(w, "Parking lot\t%s\n", m.ParkingLot)
+	fmt.Fprintf(w, "Handler\t%s\n", m.Handler)
+	fmt.Fprintf(w, "App\t%s\n", m.AppID)
+	fmt.Fprintf(w, "Reason\t%s\n", m.Reason)
+	fmt.Fprintf(w, "Last error\t%s\n", m.LastError)
+	fmt.Fprintf(w, "Attempts\t%d\n", m.Attempts)
+	fmt.Fprintf(w, "First failure\t%s\n", formatTime(m.FirstFailureAt))
+	fmt.Fprintf(w, "Last failure\t%s\n", formatTime(m.LastFailureAt))
+	fmt.Fprintf(w, "Parked at\t%s\n", formatTime(m.ParkedAt))
+	fmt.Fprintf(w, "Content type\t%s\n", m.ContentType)
+	fmt.Fprintf(w, "Content encoding\t%s\n", m.ContentEncoding)
+	for k, v := range m.Headers {
+		fmt.Fprintf(w, "Header %s\t%v\n", k, v)
+	}
+	_ = w.Flush()
+	if m.ContentEncoding != "" {
+		fmt.Printf("\n<%d bytes encoded with %s>\n", len(m.Body), m.ContentEncoding)
+		return
+	}
+	fmt.Printf("\n%s\n", m.Body)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format(time.RFC3339Nano)
+}
@@ -0,0 +1,153 @@
+package saga
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Content types of the built-in codecs.
+const (
+	ContentTypeJSON     = "application/json"
+	ContentTypeMsgpack  = "application/msgpack"
+	ContentTypeProtobuf = "application/x-protobuf"
+)
+
+// Codec encodes and decodes the payloads. The publisher sets the ContentType of the messages to the
+// one of its codec and the consumers decode every delivery with the codec of its ContentType, so
+// microservices using different codecs can share the same events, see Opts.Codec, Opts.Codecs and
+// RegisterCodec.
+type Codec interface {
+	// ContentType is the content type of the encoded payloads, e.g. "application/json".
+	ContentType() string
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// JSONCodec encodes the payloads with encoding/json, it is the default codec.
+type JSONCodec struct{}
+
+func (JSONCodec) ContentType() string { return ContentTypeJSON }
+
+func (JSONCodec) Marshal(v any) ([]byte, error) { return json.Marshal(v) }
+
+func (JSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+
+// MsgpackCodec encodes the payloads with MessagePack. It uses the json struct tags, so the payloads of
+// the event package need no changes and the field names match the JSON ones.
+type MsgpackCodec struct{}
+
+func (MsgpackCodec) ContentType() string { return ContentTypeMsgpack }
+
+func (MsgpackCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (MsgpackCodec) Unmarshal(data []byte, v any) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
+
+// ProtobufCodec encodes the payloads with Protocol Buffers, they must be generated proto.Message types.
+// It cannot decode the Payload map of the handlers registered with On, use Decode or OnEvent instead.
+type ProtobufCodec struct{}
+
+func (ProtobufCodec) ContentType() string { return ContentTypeProtobuf }
+
+func (ProtobufCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("protobuf codec: %T is not a proto.Message", v)
+	}
+	return proto.Marshal(m)
+}
+
+func (ProtobufCodec) Unmarshal(data []byte, v any) error {
+	if m, ok := v.(proto.Message); ok {
+		return proto.Unmarshal(data, m)
+	}
+	// a pointer to a message pointer, e.g. the payload of OnEvent when T is a pointer
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Pointer {
+		if rv.Elem().IsNil() {
+			rv.Elem().Set(reflect.New(rv.Elem().Type().Elem()))
+		}
+		if m, ok := rv.Elem().Interface().(proto.Message); ok {
+			return proto.Unmarshal(data, m)
+		}
+	}
+	return fmt.Errorf("protobuf codec: %T is not a proto.Message", v)
+}
+
+func codecOrJSON(c Codec) Codec {
+	if c == nil {
+		return JSONCodec{}
+	}
+	return c
+}
+
+var (
+	codecsMu sync.RWMutex
+	// registeredCodecs decode the deliveries of every Transactional, see RegisterCodec.
+	registeredCodecs = newCodecs(MsgpackCodec{}, ProtobufCodec{})
+)
+
+// RegisterCodec registers the codec decoding the deliveries of its content type in every Transactional,
+// replacing the one registered for it. JSONCodec, MsgpackCodec and ProtobufCodec are registered, the
+// codecs of Opts.Codec and Opts.Codecs take precedence in their Transactional.
+func RegisterCodec(c Codec) {
+	if c == nil {
+		return
+	}
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	registeredCodecs[mediaType(c.ContentType())] = c
+}
+
+// newCodecs returns the codecs by content type, JSONCodec is always included.
+func newCodecs(codecs ...Codec) map[string]Codec {
+	byContentType := map[string]Codec{ContentTypeJSON: JSONCodec{}}
+	for _, c := range codecs {
+		if c != nil {
+			byContentType[mediaType(c.ContentType())] = c
+		}
+	}
+	return byContentType
+}
+
+// codecFor returns the codec of the content type of a delivery, JSONCodec when it is empty: the one of
+// the Transactional, or else the registered one.
+func (t *Transactional) codecFor(contentType string) (Codec, error) {
+	if contentType == "" {
+		return JSONCodec{}, nil
+	}
+	mt := mediaType(contentType)
+	if c, ok := t.codecs[mt]; ok {
+		return c, nil
+	}
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	if c, ok := registeredCodecs[mt]; ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("no codec registered for the content type %q", contentType)
+}
+
+// mediaType strips the parameters of a content type, e.g. "application/json; charset=utf-8".
+func mediaType(contentType string) string {
+	mt, _, _ := strings.Cut(contentType, ";")
+	return strings.ToLower(strings.TrimSpace(mt))
+}
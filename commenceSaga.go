@@ -1,6 +1,11 @@
 package saga
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+
+	"github.com/legendaryum-metaverse/saga/micro"
+)
 
 const (
 	CommenceSagaQueue Queue = "commence_saga"
@@ -18,24 +23,20 @@ type CommencePayload interface {
 	Type() SagaTitle
 }
 
-type CryptoRankingWinners struct {
-	UserID string `json:"userId"`
-	Reward string `json:"reward"`
-}
-
-type CompletedCryptoRanking struct {
-	WalletAddress string                 `json:"walletAddress"`
-	Winners       []CryptoRankingWinners `json:"winners"`
-}
+// CryptoRankingWinners and CompletedCryptoRanking are shared with the payloads of the saga commands, see micro.TransferRewardToWinnersPayload.
+type (
+	CryptoRankingWinners   = micro.CryptoRankingWinners
+	CompletedCryptoRanking = micro.CompletedCryptoRanking
+)
 
 // TransferCryptoRewardToMissionWinnerPayload is the payload for the transfer_crypto_reward_to_mission_winner event.
 type TransferCryptoRewardToMissionWinnerPayload struct {
 	// Wallet address from which rewards will be transferred
-	WalletAddress string `json:"walletAddress"`
+	WalletAddress string `json:"walletAddress" validate:"required"`
 	// ID of the user who completed the mission
-	UserID string `json:"userId"`
+	UserID string `json:"userId" validate:"required"`
 	// Amount to be transferred
-	Reward string `json:"reward"`
+	Reward string `json:"reward" validate:"required"`
 }
 
 func (TransferCryptoRewardToMissionWinnerPayload) Type() SagaTitle {
@@ -44,7 +45,7 @@ func (TransferCryptoRewardToMissionWinnerPayload) Type() SagaTitle {
 
 // TransferCryptoRewardToRankingWinnersPayload is the payload for the transfer_crypto_reward_to_ranking_winners event.
 type TransferCryptoRewardToRankingWinnersPayload struct {
-	CompletedCryptoRankings []CompletedCryptoRanking `json:"completedCryptoRankings"`
+	CompletedCryptoRankings []CompletedCryptoRanking `json:"completedCryptoRankings" validate:"dive"`
 }
 
 func (TransferCryptoRewardToRankingWinnersPayload) Type() SagaTitle {
@@ -56,16 +57,37 @@ type commenceSaga struct {
 	Payload interface{} `json:"payload"`
 }
 
-func CommenceSaga(payload CommencePayload) error {
-	channel, err := getSendChannel()
+// CommenceSaga commences the saga with the default Transactional, see SetDefault.
+//
+// Deprecated: use Transactional.CommenceSaga.
+func CommenceSaga(payload CommencePayload, opts ...PublishOption) error {
+	t, err := defaultTransactional()
 	if err != nil {
-		return fmt.Errorf("error getting send channel: %w", err)
+		return err
 	}
+	return t.CommenceSaga(payload, opts...)
+}
+
+// CommenceSaga asks the transactional microservice to commence the saga of the payload.
+// The payload is validated first like in PublishEvent, see SkipValidation.
+// It returns once the broker confirms the message, waiting up to Opts.PublishTimeout, the error
+// matches ErrUnroutable when the commence_saga queue does not exist.
+func (t *Transactional) CommenceSaga(payload CommencePayload, opts ...PublishOption) error {
+	return t.CommenceSagaContext(context.Background(), payload, opts...)
+}
+
+// CommenceSagaContext is CommenceSaga bounded by the context as well as by Opts.PublishTimeout.
+func (t *Transactional) CommenceSagaContext(ctx context.Context, payload CommencePayload, opts ...PublishOption) error {
 	title := payload.Type()
+	if !newPublishOptions(opts).skipValidation {
+		if err := validatePayload(payload); err != nil {
+			return fmt.Errorf("error commencing saga %s: %w", title, err)
+		}
+	}
 	// Para que este micro pueda realizar pasos del saga y realizar commence_saga ops las queue's deben existir, no es responsabilidad
 	// de los micros crear estos recursos, el micro "transactional" debe crear estos recursos -> "queue.CommenceSaga" en commenceSagaListener
 	// y "queue.ReplyToSaga" en startGlobalSagaStepListener
-	err = send(channel, string(CommenceSagaQueue), commenceSaga{
+	err := t.send(ctx, string(CommenceSagaQueue), commenceSaga{
 		Title:   title,
 		Payload: payload,
 	})
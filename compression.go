@@ -0,0 +1,97 @@
+package saga
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// Compression is the algorithm used to compress the large payloads, it is set in the ContentEncoding
+// of the messages and the consumers decompress them automatically.
+type Compression string
+
+const (
+	CompressionNone Compression = ""
+	CompressionGzip Compression = "gzip"
+	CompressionZstd Compression = "zstd"
+)
+
+const (
+	// DefaultCompressionThreshold is the size from which the payloads are compressed.
+	DefaultCompressionThreshold = 64 << 10 // 64 KiB
+	// DefaultMaxDecompressedSize is the size over which a compressed payload is rejected, it guards
+	// the consumers against decompression bombs.
+	DefaultMaxDecompressedSize = 64 << 20 // 64 MiB
+)
+
+// zstdEncoder is safe for concurrent use with EncodeAll.
+var zstdEncoder, _ = zstd.NewWriter(nil)
+
+// compress compresses the body with Opts.Compression when it reaches the threshold, it returns the
+// body to publish and its ContentEncoding.
+func (t *Transactional) compress(body []byte) ([]byte, string, error) {
+	if t.compression == CompressionNone || len(body) < t.compressionThreshold {
+		return body, "", nil
+	}
+	switch t.compression {
+	case CompressionGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(body); err != nil {
+			return nil, "", err
+		}
+		if err := w.Close(); err != nil {
+			return nil, "", err
+		}
+		return buf.Bytes(), string(CompressionGzip), nil
+	case CompressionZstd:
+		return zstdEncoder.EncodeAll(body, make([]byte, 0, len(body)/4)), string(CompressionZstd), nil
+	}
+	return nil, "", fmt.Errorf("unknown compression %q", t.compression)
+}
+
+// decompress returns the body of a delivery decompressed according to its ContentEncoding.
+// The error is an *Error matching ErrInvalidPayload, also when the decompressed body exceeds
+// Opts.MaxDecompressedSize.
+func (t *Transactional) decompress(contentEncoding string, body []byte) ([]byte, error) {
+	var (
+		r   io.Reader
+		err error
+	)
+	switch Compression(contentEncoding) {
+	case CompressionNone, "identity":
+		return body, nil
+	case CompressionGzip:
+		var gz *gzip.Reader
+		if gz, err = gzip.NewReader(bytes.NewReader(body)); err != nil {
+			return nil, newError(ErrInvalidPayload, "failed to decompress payload", err)
+		}
+		defer gz.Close()
+		r = gz
+	case CompressionZstd:
+		var zr *zstd.Decoder
+		zr, err = zstd.NewReader(bytes.NewReader(body),
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxMemory(uint64(t.maxDecompressedSize)))
+		if err != nil {
+			return nil, newError(ErrInvalidPayload, "failed to decompress payload", err)
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, newError(ErrInvalidPayload, "failed to decompress payload", fmt.Errorf("unsupported content encoding %q", contentEncoding))
+	}
+
+	// one byte over the limit tells an oversized payload apart from one of exactly the limit
+	decompressed, err := io.ReadAll(io.LimitReader(r, t.maxDecompressedSize+1))
+	if err != nil {
+		return nil, newError(ErrInvalidPayload, "failed to decompress payload", err)
+	}
+	if int64(len(decompressed)) > t.maxDecompressedSize {
+		return nil, newError(ErrInvalidPayload, "failed to decompress payload", fmt.Errorf("decompressed payload exceeds %d bytes", t.maxDecompressedSize))
+	}
+	return decompressed, nil
+}
@@ -1,10 +1,11 @@
 package saga
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"log"
-	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,22 +15,86 @@ import (
 )
 
 type EventHandler struct {
-	Channel *EventsConsumeChannel  `json:"channel"`
+	Channel *EventsConsumeChannel `json:"channel"`
+	// Payload is the event payload, it is decoded from Raw only for the handlers registered with On,
+	// OnUnhandled or Handle. Prefer Decode, that skips the map and keeps the precision of large integers.
 	Payload map[string]interface{} `json:"payload"`
+	// Raw is the body of the delivery, the event payload encoded with the Codec of its ContentType,
+	// JSON unless the publisher uses another Codec.
+	Raw json.RawMessage `json:"-"`
+	// Context is cancelled when the shutdown of the Transactional begins.
+	Context context.Context `json:"-"`
+	// codec decodes Raw, JSONCodec if nil.
+	codec Codec
 }
 
+// Decode decodes the event payload straight from the body into data and validates it.
+// The error is an *Error matching ErrInvalidPayload.
+//
+//	var payload event.LegendEventsScoreSubmittedPayload
+//	if err := handler.Decode(&payload); err != nil {
+//		return saga.Reject(err)
+//	}
+func (e *EventHandler) Decode(data any) error {
+	return decodeRaw(e.codec, e.Raw, data)
+}
+
+// expandEventHandler decodes the compatibility Payload of the handler from its Raw body.
+func expandEventHandler(h EventHandler) EventHandler {
+	if h.Payload == nil && len(h.Raw) > 0 {
+		if err := codecOrJSON(h.codec).Unmarshal(h.Raw, &h.Payload); err != nil {
+			fmt.Printf("Error parsing message: %s\n", err)
+		}
+	}
+	return h
+}
+
+// ParsePayload decodes the handler payload into data and validates it.
+// It panics on error, use DecodePayload to handle the error instead.
 func ParsePayload[T any](handlerPayload map[string]interface{}, data *T) *T {
+	if err := decodePayload(handlerPayload, data); err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// DecodePayload decodes the handler payload into a T and validates it.
+// The error is an *Error matching ErrInvalidPayload.
+//
+//	payload, err := saga.DecodePayload[event.SocialNewUserPayload](handler.Payload)
+func DecodePayload[T any](handlerPayload map[string]interface{}) (T, error) {
+	var data T
+	err := decodePayload(handlerPayload, &data)
+	return data, err
+}
+
+// isJSONObject reports whether the raw payload is a JSON object or null, without decoding it.
+func isJSONObject(raw []byte) bool {
+	raw = bytes.TrimLeft(raw, " \t\r\n")
+	if len(raw) == 0 {
+		return false
+	}
+	return (raw[0] == '{' || bytes.Equal(raw, []byte("null"))) && json.Valid(raw)
+}
+
+// decodeRaw decodes the raw payload with the codec into data and validates it.
+// The error is an *Error matching ErrInvalidPayload.
+func decodeRaw(codec Codec, raw []byte, data any) error {
+	if err := codecOrJSON(codec).Unmarshal(raw, data); err != nil {
+		return newError(ErrInvalidPayload, "failed to unmarshal payload", err)
+	}
+	return validatePayload(data)
+}
+
+func decodePayload(handlerPayload map[string]interface{}, data any) error {
 	jsonData, err := json.Marshal(handlerPayload)
 	if err != nil {
-		panic(fmt.Errorf("failed to marshal payload: %w", err))
+		return newError(ErrInvalidPayload, "failed to marshal payload", err)
 	}
-	if err = json.Unmarshal(jsonData, &data); err != nil {
-		panic(fmt.Errorf("failed to unmarshal payload: %w", err))
+	if err = json.Unmarshal(jsonData, data); err != nil {
+		return newError(ErrInvalidPayload, "failed to unmarshal payload", err)
 	}
-	if err = validate.Struct(data); err != nil {
-		panic(fmt.Errorf("invalid payload: %w", err))
-	}
-	return data
+	return validatePayload(data)
 }
 
 // ParseEventPayload also works, but you need to pass a reference to the variable
@@ -42,6 +107,12 @@ func ParsePayload[T any](handlerPayload map[string]interface{}, data *T) *T {
 // It does not work:
 // handler.ParsePayload(eventPayload1).
 func (e *EventHandler) ParseEventPayload(data any) {
+	if e.Raw != nil {
+		if err := codecOrJSON(e.codec).Unmarshal(e.Raw, &data); err != nil {
+			panic(err)
+		}
+		return
+	}
 	body, err := json.Marshal(e.Payload)
 	if err != nil {
 		panic(err)
@@ -52,31 +123,30 @@ func (e *EventHandler) ParseEventPayload(data any) {
 }
 
 // eventCallback handles the consumption and processing of microservice events.
-func (t *Transactional) eventCallback(msg *amqp.Delivery, emitter *Emitter[EventHandler, event.MicroserviceEvent], queueName string) {
+func (t *Transactional) eventCallback(channel *amqp.Channel, msg *amqp.Delivery, emitter *Emitter[EventHandler, event.MicroserviceEvent], queueName string) {
 	if msg == nil {
 		fmt.Println("Message not available")
 		return
 	}
 
-	var eventPayload map[string]interface{}
-	if err := json.Unmarshal(msg.Body, &eventPayload); err != nil {
+	body, err := t.decompress(msg.ContentEncoding, msg.Body)
+	var codec Codec
+	if err == nil {
+		codec, err = t.codecFor(msg.ContentType)
+	}
+	if err == nil && codec.ContentType() == ContentTypeJSON && !isJSONObject(body) {
+		err = fmt.Errorf("the body is not a JSON object")
+	}
+	if err != nil {
 		fmt.Printf("Error parsing message: %s\n", err)
-		err = t.eventsChannel.Nack(msg.DeliveryTag, false, false)
-		if err != nil {
-			fmt.Println("Error negatively acknowledging message:", err)
-			return
-		}
+		t.parkUndecodable(channel, msg, queueName, err)
 		return
 	}
 
 	eventKey, err := findEventValues(msg.Headers)
 	if err != nil {
 		fmt.Println("Invalid header value: no valid event key found")
-		err = t.eventsChannel.Nack(msg.DeliveryTag, false, false)
-		if err != nil {
-			fmt.Println("Error negatively acknowledging message:", err)
-			return
-		}
+		t.parkUndecodable(channel, msg, queueName, err)
 		return
 	}
 	if len(eventKey) != 1 {
@@ -98,6 +168,15 @@ func (t *Transactional) eventCallback(msg *amqp.Delivery, emitter *Emitter[Event
 		log.Printf("Warning: Message is missing MessageId, generating a new UUID v7 for event_id")
 		eventID = uuid.Must(uuid.NewV7()).String()
 	}
+
+	if !t.track() {
+		// shutting down, the message is requeued to be consumed by another instance
+		if err := channel.Nack(msg.DeliveryTag, false, true); err != nil {
+			fmt.Println("Error negatively acknowledging message:", err)
+		}
+		return
+	}
+
 	// Emit audit.received event automatically when event is received (before processing)
 	timestamp := uint64(time.Now().UnixMilli())
 
@@ -109,18 +188,18 @@ func (t *Transactional) eventCallback(msg *amqp.Delivery, emitter *Emitter[Event
 		QueueName:             queueName,
 		EventID:               eventID,
 	}
-	go func() {
-		// Emit the audit.received event (don't fail the main flow if audit fails)
-		if auditErr := PublishAuditEvent(&auditReceivedPayload); auditErr != nil {
-			log.Printf("Failed to emit audit.received event: %v", auditErr)
-		}
-	}()
+	// Emit the audit.received event (don't fail the main flow if audit fails)
+	t.publishAudit(&auditReceivedPayload)
 
 	responseChannel := &EventsConsumeChannel{
 		ConsumeChannel: &ConsumeChannel{
-			channel:   t.eventsChannel,
+			t:         t,
+			channel:   channel,
 			msg:       msg,
 			queueName: queueName,
+			handler:   eventType,
+			policy:    t.eventRetryPolicy(event.MicroserviceEvent(eventType)),
+			release:   t.inflight.done,
 		},
 		microservice:          string(t.Microservice),
 		eventType:             eventType,
@@ -128,9 +207,15 @@ func (t *Transactional) eventCallback(msg *amqp.Delivery, emitter *Emitter[Event
 		eventID:               eventID,
 	}
 
-	emitter.Emit(eventKey[0], EventHandler{
-		Payload: eventPayload,
+	emitter.emit(eventKey[0], EventHandler{
+		Raw:     body,
 		Channel: responseChannel,
+		Context: t.ctx,
+		codec:   codec,
+	}, func() {
+		if err := responseChannel.unhandled(); err != nil {
+			fmt.Println("Error handling message without handler:", err)
+		}
 	})
 }
 
@@ -142,7 +227,7 @@ func findEventValues(headers amqp.Table) ([]event.MicroserviceEvent, error) {
 			continue
 		}
 		val := event.MicroserviceEvent(value.(string))
-		if slices.Contains(event.MicroserviceEventValues(), val) {
+		if event.IsRegistered(val) {
 			eventValues = append(eventValues, val)
 		}
 	}
@@ -1,6 +1,7 @@
 package saga
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 
@@ -10,24 +11,60 @@ import (
 
 type CommandHandler struct {
 	Channel *MicroserviceConsumeChannel `json:"channel"`
-	Payload map[string]interface{}      `json:"payload"`
-	SagaID  int                         `json:"sagaId"`
+	// Payload is the PreviousPayload of the saga step, it is decoded from Raw only for the handlers
+	// registered with On, OnUnhandled or Handle. Prefer Decode, that skips the map.
+	Payload map[string]interface{} `json:"payload"`
+	// Raw is the PreviousPayload of the saga step as received.
+	Raw    json.RawMessage `json:"-"`
+	SagaID int             `json:"sagaId"`
+	// Context is cancelled when the shutdown of the Transactional begins.
+	Context context.Context `json:"-"`
 }
 
-func (t *Transactional) sagaCommandCallback(msg *amqp.Delivery, e *Emitter[CommandHandler, micro.StepCommand], queueName string) {
+// Decode decodes the PreviousPayload of the saga step straight into data and validates it.
+// The error is an *Error matching ErrInvalidPayload.
+func (c *CommandHandler) Decode(data any) error {
+	return decodeRaw(nil, c.Raw, data)
+}
+
+// expandCommandHandler decodes the compatibility Payload of the handler from its Raw payload.
+func expandCommandHandler(h CommandHandler) CommandHandler {
+	if h.Payload == nil && len(h.Raw) > 0 {
+		if err := json.Unmarshal(h.Raw, &h.Payload); err != nil {
+			fmt.Println("Error parsing the payload of the saga step:", err)
+		}
+	}
+	return h
+}
+
+func (t *Transactional) sagaCommandCallback(channel *amqp.Channel, msg *amqp.Delivery, e *Emitter[CommandHandler, micro.StepCommand], queueName string) {
 	if msg == nil {
 		fmt.Println("NO MSG AVAILABLE")
 		return
 	}
 
-	var currentStep SagaStep
-	err := json.Unmarshal(msg.Body, &currentStep)
+	// the saga steps are always JSON, they are published by the transactional microservice
+	var currentStep rawSagaStep
+	body, err := t.decompress(msg.ContentEncoding, msg.Body)
+	if ct := mediaType(msg.ContentType); err == nil && ct != "" && ct != ContentTypeJSON {
+		err = fmt.Errorf("unsupported content type %q for a saga step", msg.ContentType)
+	}
+	if err == nil {
+		err = json.Unmarshal(body, &currentStep)
+	}
+	if err == nil && len(currentStep.PreviousPayload) > 0 && !isJSONObject(currentStep.PreviousPayload) {
+		err = fmt.Errorf("previousPayload is not a JSON object")
+	}
 	if err != nil {
 		fmt.Println("ERROR PARSING MSG", err)
-		err = t.sagaChannel.Nack(msg.DeliveryTag, false, false)
-		if err != nil {
+		t.parkUndecodable(channel, msg, queueName, err)
+		return
+	}
+
+	if !t.track() {
+		// shutting down, the message is requeued to be consumed by another instance
+		if err = channel.Nack(msg.DeliveryTag, false, true); err != nil {
 			fmt.Println("Error negatively acknowledging message:", err)
-			return
 		}
 		return
 	}
@@ -35,15 +72,24 @@ func (t *Transactional) sagaCommandCallback(msg *amqp.Delivery, e *Emitter[Comma
 	responseChannel := &MicroserviceConsumeChannel{
 		step: currentStep,
 		ConsumeChannel: &ConsumeChannel{
-			channel:   t.sagaChannel,
+			t:         t,
+			channel:   channel,
 			msg:       msg,
 			queueName: queueName,
+			handler:   currentStep.Command,
+			policy:    t.commandRetryPolicy(currentStep.Command),
+			release:   t.inflight.done,
 		},
 	}
 
-	e.Emit(currentStep.Command, CommandHandler{
+	e.emit(currentStep.Command, CommandHandler{
 		Channel: responseChannel,
-		Payload: currentStep.PreviousPayload,
+		Raw:     currentStep.PreviousPayload,
 		SagaID:  currentStep.SagaID,
+		Context: t.ctx,
+	}, func() {
+		if err := responseChannel.unhandled(currentStep.Command); err != nil {
+			fmt.Println("Error handling message without handler:", err)
+		}
 	})
 }
@@ -1,17 +1,45 @@
 package saga
 
 import (
-	"context"
 	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
 type ConsumeChannel struct {
+	t         *Transactional
 	channel   *amqp.Channel
 	msg       *amqp.Delivery
 	queueName string
+	// handler is the event or saga command of the message, stored in the failure headers.
+	handler string
+	// lastErr is the error that made the handler nack the message, stored in the failure headers.
+	lastErr error
+	// policy is the RetryPolicy of the handler, see Retry.
+	policy RetryPolicy
+	// release is called once the message is acked or nacked, it lets Shutdown drain the in-flight messages.
+	release     func()
+	releaseOnce sync.Once
+	settled     atomic.Bool
+}
+
+// settle marks the message as acked or nacked. It is also called when the ack or the nack fails: the
+// broker redelivers the message once its channel is closed, it is no longer in flight.
+func (c *ConsumeChannel) settle() {
+	c.settled.Store(true)
+	if c.release != nil {
+		c.releaseOnce.Do(c.release)
+	}
+}
+
+// isSettled reports whether the message was already acked or nacked.
+func (c *ConsumeChannel) isSettled() bool {
+	return c.settled.Load()
 }
 
 const (
@@ -35,28 +63,131 @@ const (
 	MAX_OCCURRENCE = 19
 )
 
-// math.MaxInt8.
-func (c *ConsumeChannel) NackWithDelay(delay time.Duration, maxRetries int32) (int32, time.Duration, error) {
-	err := c.channel.Nack(c.msg.DeliveryTag, false, false)
+// QueueName returns the queue the message was consumed from.
+func (c *ConsumeChannel) QueueName() string {
+	return c.queueName
+}
+
+// Headers returns the AMQP headers of the message.
+func (c *ConsumeChannel) Headers() amqp.Table {
+	return c.msg.Headers
+}
+
+// MessageID returns the AMQP message id, the event id of the events.
+func (c *ConsumeChannel) MessageID() string {
+	return c.msg.MessageId
+}
+
+// AppID returns the AMQP app id, the publisher microservice of the events.
+func (c *ConsumeChannel) AppID() string {
+	return c.msg.AppId
+}
+
+// Redelivered reports whether the broker delivered the message before without being acked.
+func (c *ConsumeChannel) Redelivered() bool {
+	return c.msg.Redelivered
+}
+
+// Attempt returns the number of the delivery of the message, 1 for the first one, increased every
+// time the message is nacked with a delay. The retry count of the message is Attempt - 1.
+func (c *ConsumeChannel) Attempt() int32 {
+	count, _ := headerInt32(c.msg.Headers, RetryCountHeader)
+	return max(count, 0) + 1
+}
+
+// Occurrence returns the occurrence of the fibonacci nack strategy of the message, 0 if it was never
+// nacked with it, see NackWithFibonacciStrategy.
+func (c *ConsumeChannel) Occurrence() int32 {
+	occurrence, _ := headerInt32(c.msg.Headers, OccurrenceHeader)
+	return max(occurrence, 0)
+}
+
+// FirstFailureAt returns the time of the first failure of the message, it reports false if it never failed.
+func (c *ConsumeChannel) FirstFailureAt() (time.Time, bool) {
+	return HeaderTime(c.msg.Headers, FirstFailureAtHeader)
+}
+
+// LastError returns the error of the handler in the last failure of the message, empty if it never
+// failed or the error is unknown.
+func (c *ConsumeChannel) LastError() string {
+	lastErr, _ := c.msg.Headers[LastErrorHeader].(string)
+	return lastErr
+}
+
+// fail records the error that made the handler nack the message.
+func (c *ConsumeChannel) fail(err error) {
+	c.lastErr = err
+}
+
+// stampFailure records the failure in the headers of the message, they travel with the retries and
+// are stored with the message when it is parked.
+func (c *ConsumeChannel) stampFailure(attempts int32) {
+	if c.msg.Headers == nil {
+		c.msg.Headers = amqp.Table{}
+	}
+	now := time.Now().UnixMilli()
+	if _, ok := c.msg.Headers[FirstFailureAtHeader]; !ok {
+		c.msg.Headers[FirstFailureAtHeader] = now
+	}
+	c.msg.Headers[LastFailureAtHeader] = now
+	c.msg.Headers[AttemptsHeader] = attempts
+	if c.lastErr != nil {
+		c.msg.Headers[LastErrorHeader] = c.lastErr.Error()
+	}
+	if c.handler != "" {
+		c.msg.Headers[HandlerHeader] = c.handler
+	}
+}
+
+// exhausted stores the message whose retries are exhausted in the parking lot queue, with the failure headers.
+func (c *ConsumeChannel) exhausted(count int32, reason string) error {
+	c.stampFailure(count)
+	err := c.park(reason)
+	if err != nil {
+		return err
+	}
+	log.Printf("%s - PARKING %s - %s", strings.ToUpper(reason), c.queueName, c.msg.Body)
+	return nil
+}
+
+// retryIn publishes a copy of the message to be delivered again after the delay and acks the message
+// once the copy is confirmed, count is its retry count. If the copy cannot be published, the message
+// is requeued. It returns the delay of the requeue strategy, see RequeueStrategy.
+func (c *ConsumeChannel) retryIn(count int32, delay time.Duration) (time.Duration, error) {
+	defer c.settle()
+	c.stampFailure(count)
+	c.msg.Headers[RetryCountHeader] = count
+	delay, err := c.publishNackEvent(delay)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error nacking message: %w", err)
+		if nackErr := c.channel.Nack(c.msg.DeliveryTag, false, true); nackErr != nil {
+			return 0, fmt.Errorf("error nacking message: %w", nackErr)
+		}
+		return 0, fmt.Errorf("error publishing nack event: %w", err)
 	}
 
-	var count int32
-	if retryCount, ok := c.msg.Headers["x-retry-count"]; ok {
-		count = retryCount.(int32)
+	err = c.channel.Ack(c.msg.DeliveryTag, false)
+	if err != nil {
+		return 0, fmt.Errorf("error acknowledging message: %w", err)
 	}
-	count++
+	return delay, nil
+}
+
+// NackWithDelay nacks the message and publishes it again after the delay. Once the message was nacked
+// more than maxRetries times it is stored in the parking lot queue of the consumer instead, with the
+// failure headers. It returns the retry count and the delay, rounded by the requeue strategy.
+func (c *ConsumeChannel) NackWithDelay(delay time.Duration, maxRetries int32) (int32, time.Duration, error) {
+	count := c.Attempt()
 
 	if count > maxRetries {
-		fmt.Printf("MAX NACK RETRIES REACHED: %d - NACKING %s - %s", maxRetries, c.queueName, c.msg.Body)
+		if err := c.exhausted(count, fmt.Sprintf("max retries reached: %d", maxRetries)); err != nil {
+			return 0, 0, err
+		}
 		return count, delay, nil
 	}
 
-	c.msg.Headers["x-retry-count"] = count
-	err = c.publishNackEvent(delay)
+	delay, err := c.retryIn(count, delay)
 	if err != nil {
-		return 0, 0, fmt.Errorf("error publishing nack event: %w", err)
+		return 0, 0, err
 	}
 	return count, delay, nil
 }
@@ -64,94 +195,34 @@ func (c *ConsumeChannel) NackWithDelay(delay time.Duration, maxRetries int32) (i
 // NackWithFibonacciStrategy is a function that handles the nack of a message with a delay that increases with the fibonacci sequence.
 // The delay is calculated as the fibonacci sequence of the occurrence of the message.
 // The occurrence is the number of times the message has been nacked.
+// Once the message was nacked more than maxRetries times it is stored in the parking lot queue of the consumer.
 // The function returns the number of retries, the delay and the occurrence of the message.
 func (c *ConsumeChannel) NackWithFibonacciStrategy(maxOccurrence, maxRetries int32) (int32, time.Duration, int32, error) {
-	err := c.channel.Nack(c.msg.DeliveryTag, false, false)
-	if err != nil {
-		return 0, 0, 0, fmt.Errorf("error nacking message: %w", err)
-	}
+	count := c.Attempt()
 
-	var count int32
-	if retryCount, ok := c.msg.Headers["x-retry-count"]; ok {
-		count = retryCount.(int32)
-	}
-	count++
-
-	var occurrence int32
-	if o, ok := c.msg.Headers["x-occurrence"]; ok {
-		occurrence = o.(int32)
-		if occurrence >= maxOccurrence {
-			// the occurrence is reset to 0 to avoid large delay in the next nack
-			occurrence = 0
-		}
+	occurrence := c.Occurrence()
+	if occurrence >= maxOccurrence {
+		// the occurrence is reset to 0 to avoid large delay in the next nack
+		occurrence = 0
 	}
 	occurrence++
 
 	delay := time.Duration(fibonacci(int(occurrence))) * time.Second
 
 	if count > maxRetries {
-		fmt.Printf("MAX NACK RETRIES REACHED: %d - NACKING %s - %s", maxRetries, c.queueName, c.msg.Body)
+		if err := c.exhausted(count, fmt.Sprintf("max retries reached: %d", maxRetries)); err != nil {
+			return 0, 0, 0, err
+		}
 		return count, delay, occurrence, nil
 	}
 
-	c.msg.Headers["x-retry-count"] = count
-	c.msg.Headers["x-occurrence"] = occurrence
-
-	err = c.publishNackEvent(delay)
+	if c.msg.Headers == nil {
+		c.msg.Headers = amqp.Table{}
+	}
+	c.msg.Headers[OccurrenceHeader] = occurrence
+	delay, err := c.retryIn(count, delay)
 	if err != nil {
-		return 0, 0, 0, fmt.Errorf("error publishing nack event: %w", err)
+		return 0, 0, 0, err
 	}
 	return count, delay, occurrence, nil
 }
-
-func (c *ConsumeChannel) publishNackEvent(delay time.Duration) error {
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-	if c.msg.Exchange == string(MatchingExchange) {
-		// the header that is deleted is the one that has all the micros listening to a certain event,
-		// otherwise the nacking reaches everyone.
-		delete(c.msg.Headers, "all-micro")
-		// deliver to one micro in particular, the one that is nacking
-		c.msg.Headers["micro"] = c.queueName
-		err := c.channel.PublishWithContext(
-			ctx,
-			string(MatchingRequeueExchange),
-			"",
-			false, // mandatory
-			false, // immediate
-			amqp.Publishing{
-				Expiration:   fmt.Sprintf("%d", delay.Milliseconds()),
-				Headers:      c.msg.Headers,
-				Body:         c.msg.Body,
-				DeliveryMode: amqp.Persistent,
-				AppId:        c.msg.AppId,
-				MessageId:    c.msg.MessageId,
-			},
-		)
-		if err != nil {
-			return fmt.Errorf("error publishing message: %w", err)
-		}
-
-	} else {
-		// is a saga event
-		err := c.channel.PublishWithContext(
-			ctx,
-			string(RequeueExchange),
-			fmt.Sprintf("%s_routing_key", c.queueName),
-			false,
-			false,
-			amqp.Publishing{
-				Expiration:   fmt.Sprintf("%d", delay.Milliseconds()),
-				Headers:      c.msg.Headers,
-				Body:         c.msg.Body,
-				DeliveryMode: amqp.Persistent,
-				AppId:        c.msg.AppId,
-				MessageId:    c.msg.MessageId,
-			},
-		)
-		if err != nil {
-			return fmt.Errorf("error publishing message: %w", err)
-		}
-	}
-	return nil
-}
@@ -2,7 +2,6 @@ package saga
 
 import (
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/legendaryum-metaverse/saga/event"
@@ -17,6 +16,7 @@ type EventsConsumeChannel struct {
 }
 
 func (m *EventsConsumeChannel) AckMessage() {
+	defer m.settle()
 	err := m.channel.Ack(m.msg.DeliveryTag, false)
 	if err != nil {
 		fmt.Println("error acknowledging message: %w", err)
@@ -33,13 +33,8 @@ func (m *EventsConsumeChannel) AckMessage() {
 		QueueName:             m.queueName,
 		EventID:               m.eventID,
 	}
-	go func() {
-		// Emit the audit event using the direct exchange method
-		if auditErr := PublishAuditEvent(&auditPayload); auditErr != nil {
-			// Log the error but don't fail the ack operation
-			log.Printf("Failed to emit audit.processed event: %v", auditErr)
-		}
-	}()
+	// Emit the audit event using the direct exchange method, don't fail the ack operation
+	m.t.publishAudit(&auditPayload)
 }
 
 // NackWithDelay wraps the base method and emits audit.dead_letter events.
@@ -47,24 +42,7 @@ func (m *EventsConsumeChannel) NackWithDelay(delay time.Duration, maxRetries int
 	count, duration, err := m.ConsumeChannel.NackWithDelay(delay, maxRetries)
 	rc := uint32(count)
 	// Emit audit.dead_letter event automatically
-	timestamp := uint64(time.Now().UnixMilli())
-
-	auditPayload := event.AuditDeadLetterPayload{
-		PublisherMicroservice: m.publisherMicroservice,
-		RejectorMicroservice:  m.microservice,
-		RejectedEvent:         m.eventType,
-		RejectedAt:            timestamp,
-		QueueName:             m.queueName,
-		RejectionReason:       "delay",
-		RetryCount:            &rc,
-		EventID:               m.eventID,
-	}
-	go func() {
-		// Emit the audit event (don't fail if audit fails)
-		if auditErr := PublishAuditEvent(&auditPayload); auditErr != nil {
-			log.Printf("Failed to emit audit.dead_letter event: %v", auditErr)
-		}
-	}()
+	m.publishDeadLetter("delay", &rc)
 
 	return count, duration, err
 }
@@ -74,6 +52,39 @@ func (m *EventsConsumeChannel) NackWithFibonacciStrategy(maxOccurrence, maxRetri
 	count, duration, occurrence, err := m.ConsumeChannel.NackWithFibonacciStrategy(maxOccurrence, maxRetries)
 	rc := uint32(count)
 	// Emit audit.dead_letter event automatically
+	m.publishDeadLetter("fibonacci_strategy", &rc)
+
+	return count, duration, occurrence, err
+}
+
+// Retry wraps the base method and emits audit.dead_letter events, the rejection reason describes the
+// outcome of the RetryPolicy.
+func (m *EventsConsumeChannel) Retry(err error) (int32, time.Duration, error) {
+	count, delay, reason, retryErr := m.ConsumeChannel.retry(err)
+	rc := uint32(count)
+	// Emit audit.dead_letter event automatically
+	m.publishDeadLetter(reason, &rc)
+
+	return count, delay, retryErr
+}
+
+// EventType returns the event of the message.
+func (m *EventsConsumeChannel) EventType() event.MicroserviceEvent {
+	return event.MicroserviceEvent(m.eventType)
+}
+
+// PublisherMicroservice returns the microservice that published the event, "unknown" if it is not set.
+func (m *EventsConsumeChannel) PublisherMicroservice() string {
+	return m.publisherMicroservice
+}
+
+// EventID returns the id tracking the event across its lifecycle, see the audit events.
+func (m *EventsConsumeChannel) EventID() string {
+	return m.eventID
+}
+
+// publishDeadLetter emits the audit.dead_letter event of the message (don't fail if audit fails).
+func (m *EventsConsumeChannel) publishDeadLetter(reason string, retryCount *uint32) {
 	timestamp := uint64(time.Now().UnixMilli())
 
 	auditPayload := event.AuditDeadLetterPayload{
@@ -82,17 +93,9 @@ func (m *EventsConsumeChannel) NackWithFibonacciStrategy(maxOccurrence, maxRetri
 		RejectedEvent:         m.eventType,
 		RejectedAt:            timestamp,
 		QueueName:             m.queueName,
-		RejectionReason:       "fibonacci_strategy",
-		RetryCount:            &rc,
+		RejectionReason:       reason,
+		RetryCount:            retryCount,
 		EventID:               m.eventID,
 	}
-
-	go func() {
-		// Emit the audit event (don't fail if audit fails)
-		if auditErr := PublishAuditEvent(&auditPayload); auditErr != nil {
-			log.Printf("Failed to emit audit.dead_letter event: %v", auditErr)
-		}
-	}()
-
-	return count, duration, occurrence, err
+	m.t.publishAudit(&auditPayload)
 }
@@ -1,7 +1,10 @@
 package saga
 
 import (
+	"encoding/json"
 	"fmt"
+
+	"github.com/legendaryum-metaverse/saga/micro"
 )
 
 type (
@@ -11,14 +14,24 @@ type (
 
 type MicroserviceConsumeChannel struct {
 	*ConsumeChannel
-	step SagaStep
+	step rawSagaStep
 }
 
 type NextStepPayload = map[string]interface{}
 
+// AckMessage replies the saga step with the payload for the next step and acks the message. The message
+// is parked when the payload cannot be encoded, and retried with the RetryPolicy of the command when the
+// reply cannot be sent.
 func (m *MicroserviceConsumeChannel) AckMessage(payloadForNextStep NextStepPayload) {
+	defer m.settle()
 	m.step.Status = Success
-	previousPayload := m.step.PreviousPayload
+	// only the keys of the previous payload are decoded, their values are passed through as they are
+	var previousPayload map[string]json.RawMessage
+	if len(m.step.PreviousPayload) > 0 {
+		if err := json.Unmarshal(m.step.PreviousPayload, &previousPayload); err != nil {
+			fmt.Println("Error parsing the previous payload of the saga step:", err)
+		}
+	}
 	metaData := make(map[string]interface{})
 
 	for key, value := range previousPayload {
@@ -31,19 +44,35 @@ func (m *MicroserviceConsumeChannel) AckMessage(payloadForNextStep NextStepPaylo
 		metaData[key] = value
 	}
 
-	m.step.Payload = metaData
+	payload, err := json.Marshal(metaData)
+	if err != nil {
+		fmt.Println("Error encoding the payload for the next step:", err)
+		m.fail(err)
+		if parkErr := m.exhausted(m.Attempt(), fmt.Sprintf("invalid payload for the next step: %v", err)); parkErr != nil {
+			fmt.Println("Error parking the saga step:", parkErr)
+		}
+		return
+	}
+	m.step.Payload = payload
 	// Para que este micro pueda realizar pasos del saga y realizar commence_saga ops las queue's deben existir, no es responsabilidad
 	// de los micros crear estos recursos, el micro "transactional" debe crear estos recursos -> "queue.CommenceSaga" en commenceSagaListener
 	// y "queue.ReplyToSaga" en startGlobalSagaStepListener
-	err := m.sendToQueue(ReplyToSagaQ, m.step)
+	err = m.sendToQueue(ReplyToSagaQ, m.step)
 	if err != nil {
-		// TODO: reenqueue message o manejar mejor el error
+		fmt.Println("Error sending the saga step to the reply queue:", err)
+		if _, _, retryErr := m.Retry(err); retryErr != nil {
+			fmt.Println("Error nacking the saga step:", retryErr)
+		}
 		return
 	}
 
 	err = m.channel.Ack(m.msg.DeliveryTag, false)
 	if err != nil {
-		// TODO: reenqueue message
 		fmt.Println("Error acknowledging message:", err)
 	}
 }
+
+// Command returns the saga command of the message.
+func (m *MicroserviceConsumeChannel) Command() micro.StepCommand {
+	return m.step.Command
+}
@@ -73,6 +73,19 @@ func (t *Transactional) createAuditLoggingResources() error {
 		return fmt.Errorf("failed to declare audit.dead_letter queue: %w", err)
 	}
 
+	// Create separate queue for audit.replayed events
+	_, err = t.eventsChannel.QueueDeclare(
+		string(AuditReplayedCommandsQ), // name
+		true,                           // durable
+		false,                          // delete when unused
+		false,                          // exclusive
+		false,                          // no-wait
+		nil,                            // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to declare audit.replayed queue: %w", err)
+	}
+
 	// Bind each queue to its specific routing key
 	err = t.eventsChannel.QueueBind(
 		string(AuditPublishedCommandsQ), // queue name
@@ -118,5 +131,16 @@ func (t *Transactional) createAuditLoggingResources() error {
 		return fmt.Errorf("failed to bind audit.dead_letter queue: %w", err)
 	}
 
+	err = t.eventsChannel.QueueBind(
+		string(AuditReplayedCommandsQ), // queue name
+		"audit.replayed",               // routing key
+		string(AuditExchange),          // exchange
+		false,                          // no-wait
+		nil,                            // arguments
+	)
+	if err != nil {
+		return fmt.Errorf("failed to bind audit.replayed queue: %w", err)
+	}
+
 	return nil
 }
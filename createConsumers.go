@@ -34,6 +34,8 @@ func (t *Transactional) createConsumers(consumers []QueueConsumerProps) error {
 		}
 
 		// Set up requeue mechanism by creating a requeue exchange and binding requeue queue to it.
+		// The requeue queue is used by RequeueExpiration, it is kept for the messages requeued by the
+		// microservices that still use it.
 		err = t.sagaChannel.ExchangeDeclare(string(RequeueExchange), "direct", true, false, false, false, nil)
 		if err != nil {
 			return err
@@ -49,7 +51,20 @@ func (t *Transactional) createConsumers(consumers []QueueConsumerProps) error {
 			return err
 		}
 
+		err = t.createRequeue(t.sagaChannel, queueName)
+		if err != nil {
+			return err
+		}
+
+		// Messages that cannot be processed are stored in the parking lot queue.
+		_, err = t.sagaChannel.QueueDeclare(getParkingLotQueue(queueName), true, false, false, false, nil)
+		if err != nil {
+			return err
+		}
+
+		t.mu.Lock()
 		t.healthCheckQueue = queueName
+		t.mu.Unlock()
 	}
 	return nil
 }
@@ -10,12 +10,14 @@ import (
 	"github.com/legendaryum-metaverse/saga/event"
 )
 
-func getEventKey(event event.MicroserviceEvent) string {
+// EventHeaderKey returns the header that routes the event in the MatchingExchange, its value is the
+// event itself.
+func EventHeaderKey(event event.MicroserviceEvent) string {
 	return strings.ToUpper(string(event))
 }
 
 func getEventObject(event event.MicroserviceEvent) amqp.Table {
-	key := getEventKey(event)
+	key := EventHeaderKey(event)
 	return amqp.Table{key: string(event)}
 }
 
@@ -34,12 +36,22 @@ func (t *Transactional) createHeaderConsumer(queueName string, events []event.Mi
 	if err != nil {
 		return fmt.Errorf("failed to declare queue %s: %w", queueName, err)
 	}
+	// the requeue queue is used by RequeueExpiration, see createConsumers
 	_, err = t.eventsChannel.QueueDeclare(requeueQueue, true, false, false, false, amqp.Table{
 		"x-dead-letter-exchange": string(MatchingExchange),
 	})
 	if err != nil {
 		return fmt.Errorf("failed to declare requeue queue %s: %w", requeueQueue, err)
 	}
+	err = t.createRequeue(t.eventsChannel, queueName)
+	if err != nil {
+		return err
+	}
+	parkingLotQueue := getParkingLotQueue(queueName)
+	_, err = t.eventsChannel.QueueDeclare(parkingLotQueue, true, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to declare parking lot queue %s: %w", parkingLotQueue, err)
+	}
 
 	// Handle individual events
 	for _, ev := range event.MicroserviceEventValues() {
@@ -123,6 +135,8 @@ func (t *Transactional) createHeaderConsumer(queueName string, events []event.Mi
 			}
 		}
 	}
+	t.mu.Lock()
 	t.healthCheckQueue = queueName
+	t.mu.Unlock()
 	return nil
 }
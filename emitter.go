@@ -1,19 +1,74 @@
 package saga
 
 import (
+	"runtime/debug"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
+// Handler handles the messages of an event or saga command, T is EventHandler or CommandHandler.
+type Handler[T any] func(T)
+
+// Middleware wraps the handlers of an Emitter. It can run code before and after next, or
+// short-circuit the message by acking or nacking it through its Channel without calling next.
+// The middleware itself is called once per registered handler, when the handler is registered and
+// on every Use, and the handler it returns once per message.
+//
+//	emitter.Use(func(next saga.Handler[saga.EventHandler]) saga.Handler[saga.EventHandler] {
+//		return func(h saga.EventHandler) {
+//			start := time.Now()
+//			next(h)
+//			log.Printf("%s handled in %s", h.Channel.EventType(), time.Since(start))
+//		}
+//	})
+type Middleware[T any] func(next Handler[T]) Handler[T]
+
 type Emitter[T any, U comparable] struct {
 	events map[U]chan T
 	// https://go.dev/doc/effective_go#data
 	// sync.Mutex does not have an explicit constructor or Init method. Instead, the zero value for a sync.Mutex is defined to be an unlocked mutex.
 	mu sync.Mutex
+	// buffer is the capacity of every event channel. It is the prefetch count of the consumer, as
+	// there are never more unacked deliveries than that, Emit never blocks the consumer and a slow
+	// event does not stall the others.
+	buffer int
+	// concurrency is the number of workers running the handler of each event, defaultConcurrency
+	// for the events not in the map.
+	concurrency        map[U]int
+	defaultConcurrency int
+	// fallback receives the data of the events without handler, see OnUnhandled.
+	fallback chan T
+	// registered is closed and replaced every time a handler is registered, it wakes up the
+	// data waiting for the handler of its event during the grace period, which Run ends.
+	registered  chan struct{}
+	graceEndsAt time.Time
+	// recovered is called with the data, the recovered value and the stack when a handler panics,
+	// the worker keeps running.
+	recovered func(data T, r any, stack []byte)
+	// middlewares wrap every handler, the first one is the outermost.
+	middlewares []Middleware[T]
+	// chains are the registered handlers wrapped with the middlewares, Use wraps them again.
+	chains []*chain[T]
+	// settle acks or nacks the message with the error returned by a handler registered with Handle.
+	settle func(data T, err error)
+	// expand decodes the compatibility fields of the data from its raw payload, it runs before the
+	// middlewares of the handlers registered with On and OnUnhandled only; the typed handlers
+	// decode the raw payload themselves.
+	expand func(data T) T
 }
 
-func newEmitter[T any, U comparable]() *Emitter[T, U] {
+func newEmitter[T any, U comparable](buffer int, concurrency map[U]int, defaultConcurrency int, gracePeriod time.Duration) *Emitter[T, U] {
+	if defaultConcurrency <= 0 {
+		defaultConcurrency = 1
+	}
 	return &Emitter[T, U]{
-		events: make(map[U]chan T),
+		events:             make(map[U]chan T),
+		buffer:             buffer,
+		concurrency:        concurrency,
+		defaultConcurrency: defaultConcurrency,
+		registered:         make(chan struct{}),
+		graceEndsAt:        time.Now().Add(gracePeriod),
 	}
 }
 
@@ -22,25 +77,189 @@ func (e *Emitter[T, U]) on(event U) chan T {
 	defer e.mu.Unlock()
 
 	if _, ok := e.events[event]; !ok {
-		e.events[event] = make(chan T)
+		e.events[event] = make(chan T, e.buffer)
+		e.notifyRegistered()
 	}
 
 	return e.events[event]
 }
 
+// notifyRegistered wakes up the emits waiting for a handler, the caller must hold the lock.
+func (e *Emitter[T, U]) notifyRegistered() {
+	close(e.registered)
+	e.registered = make(chan struct{})
+}
+
+// On registers the handler of the event. The handler runs in a pool of workers, its size is the
+// concurrency of the event (Opts.EventConcurrency or Opts.CommandConcurrency) or Opts.Concurrency,
+// so up to that number of messages of the event are handled at the same time.
 func (e *Emitter[T, U]) On(event U, handler func(T)) {
+	e.register(event, handler, true)
+}
+
+// register starts the workers of the handler of the event, expand tells whether the handler needs
+// the compatibility fields of the data.
+func (e *Emitter[T, U]) register(event U, handler func(T), expand bool) {
 	ch := e.on(event)
+	workers := e.defaultConcurrency
+	if n, ok := e.concurrency[event]; ok && n > 0 {
+		workers = n
+	}
+	e.work(ch, workers, handler, expand)
+}
+
+// OnUnhandled registers the fallback handler of the events that have no handler registered with On.
+// Without a fallback handler those messages follow the Opts.Unhandled policy.
+func (e *Emitter[T, U]) OnUnhandled(handler func(T)) {
+	e.mu.Lock()
+	if e.fallback == nil {
+		e.fallback = make(chan T, e.buffer)
+		e.notifyRegistered()
+	}
+	ch := e.fallback
+	e.mu.Unlock()
+	e.work(ch, e.defaultConcurrency, handler, true)
+}
+
+// Use adds middlewares to the chain wrapping every handler, including the ones already registered.
+// The middlewares run in the order they are added, the first one is the outermost.
+func (e *Emitter[T, U]) Use(middlewares ...Middleware[T]) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.middlewares = append(e.middlewares, middlewares...)
+
+	for _, c := range e.chains {
+		c.wrap(e.middlewares)
+	}
+}
+
+// chain is a handler wrapped with the middlewares, built once per registration and on every Use
+// rather than for every message.
+type chain[T any] struct {
+	handler Handler[T]
+	wrapped atomic.Pointer[Handler[T]]
+}
+
+// wrap wraps the handler with the middlewares.
+func (c *chain[T]) wrap(middlewares []Middleware[T]) {
+	handler := c.handler
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	c.wrapped.Store(&handler)
+}
+
+// addChain wraps the handler with the middlewares added so far and by the later calls to Use.
+func (e *Emitter[T, U]) addChain(handler Handler[T]) *chain[T] {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	c := &chain[T]{handler: handler}
+	c.wrap(e.middlewares)
+	e.chains = append(e.chains, c)
+	return c
+}
+
+func (e *Emitter[T, U]) work(ch chan T, workers int, handler func(T), expand bool) {
+	c := e.addChain(handler)
+	for range workers {
+		go func() {
+			for data := range ch {
+				if expand && e.expand != nil {
+					data = e.expand(data)
+				}
+				e.handle(c, data)
+			}
+		}()
+	}
+}
+
+func (e *Emitter[T, U]) handle(c *chain[T], data T) {
+	defer func() {
+		if r := recover(); r != nil {
+			if e.recovered == nil {
+				panic(r)
+			}
+			e.recovered(data, r, debug.Stack())
+		}
+	}()
+	(*c.wrapped.Load())(data)
+}
+
+// Emit hands the data to the handler of the event, or to the fallback handler. It returns false
+// when there is none.
+func (e *Emitter[T, U]) Emit(event U, data T) bool {
+	e.mu.Lock()
+	ch, ok := e.handler(event)
+	e.mu.Unlock()
+	if ok {
+		ch <- data
+	}
+	return ok
+}
+
+// handler returns the channel of the handler of the event, or of the fallback handler, the caller
+// must hold the lock.
+func (e *Emitter[T, U]) handler(event U) (chan T, bool) {
+	ch, ok := e.events[event]
+	if !ok && e.fallback != nil {
+		ch, ok = e.fallback, true
+	}
+	return ch, ok
+}
+
+// emit is Emit for the consumers, unhandled is called when the event has no handler. During the
+// grace period, the data waits in background for its handler to be registered, so that the
+// consumer keeps delivering the other messages. The waiting message is not settled, it holds one
+// of the prefetch slots of the channel until then.
+func (e *Emitter[T, U]) emit(event U, data T, unhandled func()) {
+	registered, wait := e.deliver(event, data, unhandled)
+	if registered == nil {
+		return
+	}
 	go func() {
-		for data := range ch {
-			handler(data)
+		for registered != nil {
+			select {
+			case <-registered:
+			case <-time.After(wait):
+			}
+			registered, wait = e.deliver(event, data, unhandled)
 		}
 	}()
 }
 
-func (e *Emitter[T, U]) Emit(event U, data T) {
+// deliver hands the data to its handler, or calls unhandled once the grace period is over. Otherwise
+// it returns the channel closed when a handler is registered and the time left of the grace period.
+func (e *Emitter[T, U]) deliver(event U, data T, unhandled func()) (<-chan struct{}, time.Duration) {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-	if ch, ok := e.events[event]; ok {
+	ch, ok := e.handler(event)
+	registered, wait := e.registered, time.Until(e.graceEndsAt)
+	e.mu.Unlock()
+
+	switch {
+	case ok:
 		ch <- data
+	case wait <= 0:
+		unhandled()
+	default:
+		return registered, wait
+	}
+	return nil, 0
+}
+
+// endGrace ends the grace period, the data waiting for its handler is handed to unhandled.
+func (e *Emitter[T, U]) endGrace() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if now := time.Now(); now.Before(e.graceEndsAt) {
+		e.graceEndsAt = now
+		e.notifyRegistered()
 	}
 }
+
+// handles reports whether the event has a handler registered with On.
+func (e *Emitter[T, U]) handles(event U) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	_, ok := e.events[event]
+	return ok
+}
@@ -0,0 +1,47 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors of the error-returning API, use errors.Is to check them.
+var (
+	// ErrInvalidOptions is returned when the Opts do not pass the validation.
+	ErrInvalidOptions = errors.New("invalid options")
+	// ErrBrokerUnavailable is returned when the connection or a channel to RabbitMQ cannot be opened.
+	ErrBrokerUnavailable = errors.New("broker unavailable")
+	// ErrTopology is returned when the exchanges, queues or bindings cannot be asserted.
+	ErrTopology = errors.New("topology error")
+	// ErrInvalidPayload is returned when a payload cannot be decoded or does not pass the validation.
+	ErrInvalidPayload = errors.New("invalid payload")
+	// ErrUnroutable is returned when a published message was returned by the broker because it did not reach any queue.
+	ErrUnroutable = errors.New("unroutable message")
+	// ErrNacked is returned when the broker nacked a published message.
+	ErrNacked = errors.New("message nacked by the broker")
+	// ErrMissingHandlers is returned when a subscribed event has no handler registered.
+	ErrMissingHandlers = errors.New("missing handlers")
+)
+
+// Error is the error returned by the error-returning API, use errors.As to inspect it.
+// It matches both its Kind, one of the sentinel errors, and the underlying Err.
+type Error struct {
+	// Kind is one of the sentinel errors.
+	Kind error
+	// Msg describes the operation that failed.
+	Msg string
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %v", e.Msg, e.Err)
+}
+
+func (e *Error) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
+
+func newError(kind error, msg string, err error) *Error {
+	return &Error{Kind: kind, Msg: msg, Err: err}
+}
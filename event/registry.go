@@ -0,0 +1,57 @@
+package event
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	registryMu sync.RWMutex
+	// registered keeps the events in registration order, the built-in ones first.
+	registered   []MicroserviceEvent
+	payloadTypes = map[MicroserviceEvent]reflect.Type{}
+)
+
+// Register registers the events of the payloads, so that a microservice can publish and subscribe to
+// events that are not built into this package. It is meant to be called at init:
+//
+//	func init() {
+//		event.Register(OrderShippedPayload{})
+//	}
+//
+// The event is payload.Type(); registering an event again replaces its payload type.
+func Register(payloads ...PayloadEvent) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	for _, payload := range payloads {
+		register(payload)
+	}
+}
+
+func register(payload PayloadEvent) {
+	name := payload.Type()
+	if name == "" {
+		panic(fmt.Sprintf("event: %T has an empty event type", payload))
+	}
+	if _, ok := payloadTypes[name]; !ok {
+		registered = append(registered, name)
+	}
+	payloadTypes[name] = reflect.Indirect(reflect.ValueOf(payload)).Type()
+}
+
+// IsRegistered reports whether the event is built-in or was registered with Register.
+func IsRegistered(name MicroserviceEvent) bool {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	_, ok := payloadTypes[name]
+	return ok
+}
+
+// Lookup returns the payload type of the event.
+func Lookup(name MicroserviceEvent) (reflect.Type, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	payload, ok := payloadTypes[name]
+	return payload, ok
+}
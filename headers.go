@@ -0,0 +1,110 @@
+package saga
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Retry headers, shared with the saga libraries of the other languages, see docs/headers.md.
+const (
+	// RetryCountHeader is the number of times the message was nacked with a delay.
+	RetryCountHeader = "x-retry-count"
+	// OccurrenceHeader is the occurrence of the fibonacci nack strategy, see NackWithFibonacciStrategy.
+	OccurrenceHeader = "x-occurrence"
+	// DelayHeader is the delay in milliseconds of RequeueDelayedExchange.
+	DelayHeader = "x-delay"
+)
+
+// HeaderInt reads an integer header whatever the numeric type it was encoded with: the broker decodes
+// the integers with the size the publisher encoded them with, which depends on its language, and a
+// message republished by hand may carry them as floats, decimals or strings.
+// It reports false when the header is missing or is not an integer.
+func HeaderInt(headers amqp.Table, key string) (int64, bool) {
+	switch n := headers[key].(type) {
+	case int8:
+		return int64(n), true
+	case int16:
+		return int64(n), true
+	case int32:
+		return int64(n), true
+	case int64:
+		return n, true
+	case int:
+		return int64(n), true
+	case uint8:
+		return int64(n), true
+	case uint16:
+		return int64(n), true
+	case uint32:
+		return int64(n), true
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case uint:
+		if uint64(n) > math.MaxInt64 {
+			return 0, false
+		}
+		return int64(n), true
+	case float32:
+		return floatInt(float64(n))
+	case float64:
+		return floatInt(n)
+	case amqp.Decimal:
+		return floatInt(float64(n.Value) / math.Pow10(int(n.Scale)))
+	case string:
+		return stringInt(n)
+	case []byte:
+		return stringInt(string(n))
+	}
+	return 0, false
+}
+
+func floatInt(f float64) (int64, bool) {
+	if f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+		return 0, false
+	}
+	return int64(f), true
+}
+
+func stringInt(s string) (int64, bool) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return n, true
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return floatInt(f)
+	}
+	return 0, false
+}
+
+// headerInt32 is HeaderInt for the counters, it reports false when the header does not fit an int32.
+func headerInt32(headers amqp.Table, key string) (int32, bool) {
+	n, ok := HeaderInt(headers, key)
+	if !ok || n < math.MinInt32 || n > math.MaxInt32 {
+		return 0, false
+	}
+	return int32(n), true
+}
+
+// HeaderTime reads a timestamp header, a UNIX time in milliseconds encoded as HeaderInt reads it, an
+// AMQP timestamp or an RFC 3339 string. It reports false when the header is missing or is not a time.
+func HeaderTime(headers amqp.Table, key string) (time.Time, bool) {
+	switch v := headers[key].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(v)); err == nil {
+			return t, true
+		}
+	}
+	if ms, ok := HeaderInt(headers, key); ok {
+		return time.UnixMilli(ms), true
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,181 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga/event"
+)
+
+// DefaultShutdownTimeout is the time Run and StopRabbitMQ wait for the in-flight messages to be settled.
+const DefaultShutdownTimeout = 30 * time.Second
+
+// tracker counts in-flight operations so that Shutdown can wait for them.
+// The zero value is ready to use.
+type tracker struct {
+	mu   sync.Mutex
+	n    int
+	zero chan struct{}
+}
+
+func (tr *tracker) add() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	if tr.n == 0 {
+		tr.zero = make(chan struct{})
+	}
+	tr.n++
+}
+
+func (tr *tracker) done() {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	tr.n--
+	if tr.n == 0 {
+		close(tr.zero)
+	}
+}
+
+// wait blocks until there are no in-flight operations or the context is done.
+func (tr *tracker) wait(ctx context.Context) error {
+	tr.mu.Lock()
+	if tr.n == 0 {
+		tr.mu.Unlock()
+		return nil
+	}
+	zero := tr.zero
+	tr.mu.Unlock()
+
+	select {
+	case <-zero:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Context returns the context handed to the handlers, it is cancelled when the shutdown begins.
+func (t *Transactional) Context() context.Context {
+	return t.ctx
+}
+
+// Run blocks until the context is done and then shuts down the Transactional gracefully,
+// waiting up to Opts.ShutdownTimeout for the in-flight messages, see Shutdown.
+//
+//	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+//	defer stop()
+//	err := transactional.Run(ctx)
+//
+// The handlers are expected to be registered before calling Run, it ends Opts.HandlerGracePeriod.
+// With Opts.RequireHandlers, Run shuts down right away and returns an error matching
+// ErrMissingHandlers if any of the subscribed events has no handler.
+func (t *Transactional) Run(ctx context.Context) error {
+	if t.requireHandlers {
+		if err := t.CheckHandlers(); err != nil {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), t.shutdownTimeout)
+			defer cancel()
+			return errors.Join(err, t.Shutdown(shutdownCtx))
+		}
+	}
+
+	t.endGrace()
+
+	select {
+	case <-ctx.Done():
+	case <-t.done:
+		// already stopped with Shutdown or StopRabbitMQ
+		return nil
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), t.shutdownTimeout)
+	defer cancel()
+	return t.Shutdown(shutdownCtx)
+}
+
+// endGrace ends the grace period of the emitters, the messages without handler follow the
+// Opts.Unhandled policy from now on.
+func (t *Transactional) endGrace() {
+	t.mu.Lock()
+	eventsEmitter, sagaEmitter := t.eventsEmitter, t.sagaEmitter
+	t.mu.Unlock()
+	if eventsEmitter != nil {
+		eventsEmitter.endGrace()
+	}
+	if sagaEmitter != nil {
+		sagaEmitter.endGrace()
+	}
+}
+
+// Shutdown stops the Transactional gracefully:
+//  1. the handlers context is cancelled and the consumers are cancelled (basic.cancel), the
+//     messages already prefetched are requeued without reaching the handlers.
+//  2. it waits for the in-flight messages to be acked or nacked by their handlers.
+//  3. it waits for the pending audit events to be published.
+//  4. the channels, the connection and the publisher connection are closed.
+//
+// If the context is done before the drain finishes, the channels and connections are closed anyway
+// and the context error is returned; the unsettled messages are redelivered by the broker. The
+// errors of every step are joined, closing what is already closed is not one of them.
+func (t *Transactional) Shutdown(ctx context.Context) error {
+	t.mu.Lock()
+	if t.shuttingDown {
+		t.mu.Unlock()
+		return nil
+	}
+	t.shuttingDown = true
+	eventsChannel, sagaChannel := t.eventsChannel, t.sagaChannel
+	t.mu.Unlock()
+	t.cancel()
+	t.stopOnce.Do(func() {
+		close(t.done)
+	})
+
+	if eventsChannel != nil {
+		if err := eventsChannel.Cancel(t.eventsConsumerTag, false); err != nil {
+			log.Printf("Failed to cancel the events consumer: %v", err)
+		}
+	}
+	if sagaChannel != nil {
+		if err := sagaChannel.Cancel(t.sagaConsumerTag, false); err != nil {
+			log.Printf("Failed to cancel the saga commands consumer: %v", err)
+		}
+	}
+
+	drainErr := t.inflight.wait(ctx)
+	if drainErr != nil {
+		log.Printf("Shutdown deadline reached with messages still in flight: %v", drainErr)
+	} else {
+		drainErr = t.audits.wait(ctx)
+		if drainErr != nil {
+			log.Printf("Shutdown deadline reached with audit events still pending: %v", drainErr)
+		}
+	}
+
+	return errors.Join(t.close(), ignoreClosed(t.publisher.close()), drainErr)
+}
+
+// track registers a delivery that reached a handler, it returns false when the Transactional
+// is shutting down and the delivery must be requeued instead.
+func (t *Transactional) track() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.shuttingDown {
+		return false
+	}
+	t.inflight.add()
+	return true
+}
+
+// publishAudit emits the audit event in background, never failing the main flow.
+// Shutdown waits for the pending audit events before closing the connection.
+func (t *Transactional) publishAudit(payload event.PayloadEvent) {
+	t.audits.add()
+	go func() {
+		defer t.audits.done()
+		if auditErr := t.PublishAuditEvent(payload); auditErr != nil {
+			log.Printf("Failed to emit %s event: %v", payload.Type(), auditErr)
+		}
+	}()
+}
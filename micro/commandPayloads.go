@@ -0,0 +1,72 @@
+package micro
+
+import (
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// Command binds a StepCommand to the payload it receives from the previous step, P, and the result
+// it passes to the next step, R. It is created with RegisterCommand and used with saga.OnCommand.
+type Command[P, R any] struct {
+	Name StepCommand
+}
+
+// CommandSpec describes the payload and result types registered for a StepCommand.
+type CommandSpec struct {
+	Name    StepCommand
+	Payload reflect.Type
+	Result  reflect.Type
+}
+
+// Untyped is the payload or result of the commands whose shape is defined by each microservice.
+type Untyped = map[string]interface{}
+
+// NoResult is the result of the commands that pass nothing to the next step.
+type NoResult struct{}
+
+var (
+	commandsMu sync.RWMutex
+	commands   = map[StepCommand]CommandSpec{}
+)
+
+// RegisterCommand registers the payload and result types of the command and returns its binding.
+// Registering a command again replaces its types.
+func RegisterCommand[P, R any](name StepCommand) Command[P, R] {
+	commandsMu.Lock()
+	defer commandsMu.Unlock()
+	commands[name] = CommandSpec{
+		Name:    name,
+		Payload: reflect.TypeFor[P](),
+		Result:  reflect.TypeFor[R](),
+	}
+	return Command[P, R]{Name: name}
+}
+
+// LookupCommand returns the types registered for the command.
+func LookupCommand(name StepCommand) (CommandSpec, bool) {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+	spec, ok := commands[name]
+	return spec, ok
+}
+
+// CommandSpecs returns the types registered for every command, sorted by name.
+func CommandSpecs() []CommandSpec {
+	commandsMu.RLock()
+	defer commandsMu.RUnlock()
+	specs := make([]CommandSpec, 0, len(commands))
+	for _, spec := range commands {
+		specs = append(specs, spec)
+	}
+	slices.SortFunc(specs, func(a, b CommandSpec) int {
+		switch {
+		case a.Name < b.Name:
+			return -1
+		case a.Name > b.Name:
+			return 1
+		}
+		return 0
+	})
+	return specs
+}
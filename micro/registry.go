@@ -0,0 +1,40 @@
+package micro
+
+import (
+	"slices"
+	"sync"
+)
+
+var (
+	microservicesMu sync.RWMutex
+	microservices   = map[AvailableMicroservices]struct{}{}
+)
+
+// Register registers the microservices, so that a Transactional can be created for a microservice that
+// is not built into this package. It is meant to be called at init:
+//
+//	func init() {
+//		micro.Register("legend-orders")
+//	}
+func Register(names ...AvailableMicroservices) {
+	microservicesMu.Lock()
+	defer microservicesMu.Unlock()
+	for _, name := range names {
+		if name == "" {
+			panic("micro: empty microservice name")
+		}
+		microservices[name] = struct{}{}
+	}
+}
+
+// Microservices returns the built-in microservices and the ones registered with Register, sorted by name.
+func Microservices() []AvailableMicroservices {
+	microservicesMu.RLock()
+	defer microservicesMu.RUnlock()
+	names := make([]AvailableMicroservices, 0, len(microservices))
+	for name := range microservices {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
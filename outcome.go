@@ -0,0 +1,157 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// RetryError is returned by a handler to nack the message and retry it later, see Retry and RetryFibonacci.
+type RetryError struct {
+	Err error
+	// Delay is the delay of NackWithDelay, unused when Fibonacci is set.
+	Delay time.Duration
+	// Fibonacci nacks the message with NackWithFibonacciStrategy.
+	Fibonacci bool
+}
+
+func (e *RetryError) Error() string {
+	if e.Fibonacci {
+		return fmt.Sprintf("retry with fibonacci strategy: %v", e.Err)
+	}
+	return fmt.Sprintf("retry in %s: %v", e.Delay, e.Err)
+}
+
+func (e *RetryError) Unwrap() error {
+	return e.Err
+}
+
+// RejectError is returned by a handler to store the message in the parking lot queue, see Reject.
+type RejectError struct {
+	Err error
+}
+
+func (e *RejectError) Error() string {
+	return fmt.Sprintf("rejected: %v", e.Err)
+}
+
+func (e *RejectError) Unwrap() error {
+	return e.Err
+}
+
+// Retry makes the handler nack the message with NackWithDelay(delay, MAX_NACK_RETRIES).
+func Retry(err error, delay time.Duration) error {
+	return &RetryError{Err: err, Delay: delay}
+}
+
+// RetryFibonacci makes the handler nack the message with NackWithFibonacciStrategy(MAX_OCCURRENCE, MAX_NACK_RETRIES).
+// The errors that are neither a RetryError nor a RejectError are retried with the RetryPolicy instead.
+func RetryFibonacci(err error) error {
+	return &RetryError{Err: err, Fibonacci: true}
+}
+
+// Reject makes the handler store the message in the parking lot queue of the consumer, it will not be retried.
+func Reject(err error) error {
+	return &RejectError{Err: err}
+}
+
+// Handle registers a handler whose returned error decides the outcome of the message, so it does not
+// need to ack or nack it on every code path:
+//   - nil acks the message, with an empty NextStepPayload for the saga commands.
+//   - Retry(err, delay) nacks it with NackWithDelay.
+//   - RetryFibonacci(err) nacks it with NackWithFibonacciStrategy.
+//   - Reject(err) stores it in the parking lot queue of the consumer.
+//   - any other error retries it with the RetryPolicy of the event or saga command, see ConsumeChannel.Retry.
+//
+// The audit events of the events are emitted for each outcome. If the handler already acked or nacked
+// the message, the error is only logged.
+func (e *Emitter[T, U]) Handle(event U, handler func(T) error) {
+	e.On(event, func(data T) {
+		e.settle(data, handler(data))
+	})
+}
+
+// settleWith acks or nacks the message with the result of a handler, unless the handler already did it.
+func (m *EventsConsumeChannel) settleWith(err error) {
+	if m.isSettled() {
+		if err != nil {
+			log.Printf("Handler of %s failed after settling the message: %v", m.eventType, err)
+		}
+		return
+	}
+	if err == nil {
+		m.AckMessage()
+		return
+	}
+
+	log.Printf("Handler of %s failed: %v", m.eventType, err)
+	m.fail(err)
+	var (
+		retry    *RetryError
+		rejected *RejectError
+		nackErr  error
+	)
+	switch {
+	case errors.As(err, &rejected):
+		nackErr = m.reject(rejected.Err)
+	case errors.As(err, &retry) && !retry.Fibonacci:
+		_, _, nackErr = m.NackWithDelay(retry.Delay, MAX_NACK_RETRIES)
+	case errors.As(err, &retry):
+		_, _, _, nackErr = m.NackWithFibonacciStrategy(MAX_OCCURRENCE, MAX_NACK_RETRIES)
+	default:
+		_, _, nackErr = m.Retry(err)
+	}
+	if nackErr != nil {
+		log.Printf("Error nacking message of %s: %v", m.eventType, nackErr)
+	}
+}
+
+// reject stores the message in the parking lot queue and emits an audit.dead_letter event.
+func (m *EventsConsumeChannel) reject(cause error) error {
+	reason := fmt.Sprintf("rejected: %v", cause)
+	if err := m.park(reason); err != nil {
+		return err
+	}
+	m.publishDeadLetter(reason, nil)
+	return nil
+}
+
+// settleWith acks the message with the payload for the next step, or nacks it with the error of a handler,
+// unless the handler already did it.
+func (m *MicroserviceConsumeChannel) settleWith(err error, payloadForNextStep NextStepPayload) {
+	if m.isSettled() {
+		if err != nil {
+			log.Printf("Handler of %s failed after settling the message: %v", m.step.Command, err)
+		}
+		return
+	}
+	if err == nil {
+		if payloadForNextStep == nil {
+			payloadForNextStep = NextStepPayload{}
+		}
+		m.AckMessage(payloadForNextStep)
+		return
+	}
+
+	log.Printf("Handler of %s failed: %v", m.step.Command, err)
+	m.fail(err)
+	var (
+		retry    *RetryError
+		rejected *RejectError
+		nackErr  error
+	)
+	switch {
+	case errors.As(err, &rejected):
+		nackErr = m.park(fmt.Sprintf("rejected: %v", rejected.Err))
+	case errors.As(err, &retry) && !retry.Fibonacci:
+		_, _, nackErr = m.NackWithDelay(retry.Delay, MAX_NACK_RETRIES)
+	case errors.As(err, &retry):
+		_, _, _, nackErr = m.NackWithFibonacciStrategy(MAX_OCCURRENCE, MAX_NACK_RETRIES)
+	default:
+		_, _, nackErr = m.Retry(err)
+	}
+	if nackErr != nil {
+		log.Printf("Error nacking message of %s: %v", m.step.Command, nackErr)
+	}
+}
@@ -0,0 +1,323 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+
+	"github.com/legendaryum-metaverse/saga/event"
+)
+
+// ErrNotParked is returned by ParkedMessage when no parked message has the id.
+var ErrNotParked = errors.New("message not parked")
+
+// ParkedMessage is a message stored in a parking lot queue, with its failure metadata.
+type ParkedMessage struct {
+	// Queue is the consumer queue the message was parked from, where it is replayed to.
+	Queue string
+	// ParkingLot is the parking lot queue storing the message.
+	ParkingLot string
+	MessageID  string
+	// AppID is the publisher microservice.
+	AppID string
+	// Handler is the event or the saga command of the message.
+	Handler string
+	// Reason is why the message was parked, see ParkingReasonHeader.
+	Reason string
+	// LastError is the error of the handler in the last failure, empty when it is unknown.
+	LastError      string
+	Attempts       int64
+	FirstFailureAt time.Time
+	LastFailureAt  time.Time
+	ParkedAt       time.Time
+
+	Headers         amqp.Table
+	ContentType     string
+	ContentEncoding string
+	Body            []byte
+}
+
+// ParkedFilter selects parked messages, the zero value selects all of them.
+type ParkedFilter struct {
+	// Handler is the event or the saga command of the messages.
+	Handler string
+	// AppID is the publisher microservice of the messages.
+	AppID string
+	// Since and Until bound the time the messages were parked, they are unbounded when zero.
+	Since time.Time
+	Until time.Time
+	// Error is a substring of the last error or of the parking reason.
+	Error string
+	// MessageIDs are the ids of the messages.
+	MessageIDs []string
+	// Limit is the maximum number of messages, unlimited when zero.
+	Limit int
+}
+
+func (f *ParkedFilter) match(m *ParkedMessage) bool {
+	switch {
+	case f.Handler != "" && m.Handler != f.Handler:
+		return false
+	case f.AppID != "" && m.AppID != f.AppID:
+		return false
+	case !f.Since.IsZero() && m.ParkedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && m.ParkedAt.After(f.Until):
+		return false
+	case f.Error != "" && !strings.Contains(m.LastError, f.Error) && !strings.Contains(m.Reason, f.Error):
+		return false
+	case len(f.MessageIDs) > 0 && !slices.Contains(f.MessageIDs, m.MessageID):
+		return false
+	}
+	return true
+}
+
+// ParkedMessages lists the messages parked by the microservice, from the parking lot queues of its
+// events and saga commands consumers, in their order.
+// The messages stay in the queues: they are read without being acked and requeued once listed.
+func (t *Transactional) ParkedMessages(ctx context.Context, filter ParkedFilter) ([]ParkedMessage, error) {
+	var messages []ParkedMessage
+	_, err := t.walkParked(ctx, filter, func(m *ParkedMessage) (bool, error) {
+		messages = append(messages, *m)
+		return false, nil
+	})
+	return messages, err
+}
+
+// ParkedMessage returns the parked message with the id, the error matches ErrNotParked if there is none.
+func (t *Transactional) ParkedMessage(ctx context.Context, messageID string) (ParkedMessage, error) {
+	messages, err := t.ParkedMessages(ctx, ParkedFilter{MessageIDs: []string{messageID}, Limit: 1})
+	if err != nil {
+		return ParkedMessage{}, err
+	}
+	if len(messages) == 0 {
+		return ParkedMessage{}, fmt.Errorf("%w: %s", ErrNotParked, messageID)
+	}
+	return messages[0], nil
+}
+
+// ReplayParked publishes the parked messages selected by the filter back to the consumer queue they
+// were parked from, and removes them from the parking lot once the broker confirms them:
+//   - the events through the MatchingRequeueExchange with the micro header, so that only the
+//     microservice that parked them receives them again.
+//   - the saga commands through the RequeueExchange with the routing key of the consumer queue.
+//
+// The retry count and the age of the messages start again and an audit.replayed event is emitted for
+// each of them. It returns the number of replayed messages, the ones that fail to be published stay parked.
+// Each message waits up to Opts.PublishTimeout for its confirmation.
+func (t *Transactional) ReplayParked(ctx context.Context, filter ParkedFilter) (int, error) {
+	var errs []error
+	n, err := t.walkParked(ctx, filter, func(m *ParkedMessage) (bool, error) {
+		if err := t.replay(ctx, m); err != nil {
+			errs = append(errs, fmt.Errorf("error replaying message %s: %w", m.MessageID, err))
+			return false, nil
+		}
+		return true, nil
+	})
+	return n, errors.Join(append(errs, err)...)
+}
+
+// PurgeParked removes the parked messages selected by the filter, it returns the number of removed messages.
+func (t *Transactional) PurgeParked(ctx context.Context, filter ParkedFilter) (int, error) {
+	return t.walkParked(ctx, filter, func(*ParkedMessage) (bool, error) {
+		return true, nil
+	})
+}
+
+// parkingLots returns the consumer queues of the microservice and their parking lot queues.
+func (t *Transactional) parkingLots() [][2]string {
+	var lots [][2]string
+	for _, queue := range []string{getEventsQueueName(t.Microservice), getQueueName(t.Microservice)} {
+		lots = append(lots, [2]string{queue, getParkingLotQueue(queue)})
+	}
+	return lots
+}
+
+// walkParked reads the messages of the parking lot queues without acking them and passes the ones
+// selected by the filter to fn, which reports whether the message is removed from the queue.
+// The rest are requeued in their order once a queue is walked. It returns the number of removed messages.
+func (t *Transactional) walkParked(ctx context.Context, filter ParkedFilter, fn func(*ParkedMessage) (bool, error)) (int, error) {
+	conn := t.connection()
+	if conn == nil || conn.IsClosed() {
+		return 0, newError(ErrBrokerUnavailable, "Failed to read the parking lot", fmt.Errorf("rabbitmq is not connected"))
+	}
+	removed, selected := 0, 0
+	for _, lot := range t.parkingLots() {
+		if filter.Limit > 0 && selected >= filter.Limit {
+			break
+		}
+		ch, err := conn.Channel()
+		if err != nil {
+			return removed, newError(ErrBrokerUnavailable, "Failed to create the parking lot channel", err)
+		}
+		n, s, err := walkParkingLot(ctx, ch, lot[0], lot[1], filter, filter.Limit-selected, fn)
+		removed += n
+		selected += s
+		// closing the channel requeues the messages that were not acked or nacked
+		_ = ch.Close()
+		if err != nil {
+			return removed, err
+		}
+	}
+	return removed, nil
+}
+
+// walkParkingLot walks the messages of a parking lot queue, selecting up to limit messages, unlimited
+// when not positive. It returns the number of removed and selected messages.
+func walkParkingLot(ctx context.Context, ch *amqp.Channel, queue, parkingLot string, filter ParkedFilter, limit int, fn func(*ParkedMessage) (bool, error)) (int, int, error) {
+	q, err := ch.QueueDeclarePassive(parkingLot, true, false, false, false, nil)
+	if err != nil {
+		var amqpErr *amqp.Error
+		if errors.As(err, &amqpErr) && amqpErr.Code == amqp.NotFound {
+			// the consumer was never created
+			return 0, 0, nil
+		}
+		return 0, 0, newError(ErrTopology, "Failed to check the parking lot queue "+parkingLot, err)
+	}
+
+	removed, selected := 0, 0
+	var last uint64
+	// the messages are requeued at the end, the walk stops at the messages that were in the queue
+	for range q.Messages {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if limit > 0 && selected >= limit {
+			break
+		}
+		msg, ok, getErr := ch.Get(parkingLot, false)
+		if getErr != nil {
+			err = newError(ErrBrokerUnavailable, "Failed to get a message from "+parkingLot, getErr)
+			break
+		}
+		if !ok {
+			break
+		}
+		last = msg.DeliveryTag
+		m := newParkedMessage(queue, parkingLot, &msg)
+		if !filter.match(m) {
+			continue
+		}
+		selected++
+		remove, fnErr := fn(m)
+		if fnErr != nil {
+			err = fnErr
+			break
+		}
+		if remove {
+			if ackErr := msg.Ack(false); ackErr != nil {
+				err = fmt.Errorf("error acknowledging message: %w", ackErr)
+				break
+			}
+			removed++
+		}
+	}
+	if last > 0 && !ch.IsClosed() {
+		// requeues every message that was not removed
+		if nackErr := ch.Nack(last, true, true); nackErr != nil && err == nil {
+			err = fmt.Errorf("error requeuing the parked messages: %w", nackErr)
+		}
+	}
+	return removed, selected, err
+}
+
+func newParkedMessage(queue, parkingLot string, msg *amqp.Delivery) *ParkedMessage {
+	m := &ParkedMessage{
+		Queue:           queue,
+		ParkingLot:      parkingLot,
+		MessageID:       msg.MessageId,
+		AppID:           msg.AppId,
+		ParkedAt:        msg.Timestamp,
+		Headers:         msg.Headers,
+		ContentType:     msg.ContentType,
+		ContentEncoding: msg.ContentEncoding,
+		Body:            msg.Body,
+	}
+	m.Reason, _ = msg.Headers[ParkingReasonHeader].(string)
+	m.LastError, _ = msg.Headers[LastErrorHeader].(string)
+	m.Handler, _ = msg.Headers[HandlerHeader].(string)
+	if m.Handler == "" {
+		m.Handler = parkedEvent(msg.Headers)
+	}
+	m.Attempts, _ = HeaderInt(msg.Headers, AttemptsHeader)
+	m.FirstFailureAt, _ = HeaderTime(msg.Headers, FirstFailureAtHeader)
+	m.LastFailureAt, _ = HeaderTime(msg.Headers, LastFailureAtHeader)
+	return m
+}
+
+// parkedEvent finds the event of a message parked without the HandlerHeader, from its routing header.
+func parkedEvent(headers amqp.Table) string {
+	for k, v := range headers {
+		if ev, ok := v.(string); ok && k == EventHeaderKey(event.MicroserviceEvent(ev)) {
+			return ev
+		}
+	}
+	return ""
+}
+
+// replay publishes the parked message to its consumer queue and emits the audit.replayed event.
+func (t *Transactional) replay(ctx context.Context, m *ParkedMessage) error {
+	headers := amqp.Table{}
+	for k, v := range m.Headers {
+		headers[k] = v
+	}
+	for _, k := range []string{
+		ParkingReasonHeader, ParkingQueueHeader, ParkingExchangeHeader, ParkingRoutingKeyHeader,
+		RetryCountHeader, OccurrenceHeader, DelayHeader,
+		// the RetryPolicy.MaxAge of the replayed message counts from its next failure
+		FirstFailureAtHeader,
+	} {
+		delete(headers, k)
+	}
+
+	exchange, routingKey := string(RequeueExchange), fmt.Sprintf("%s_routing_key", m.Queue)
+	if m.Queue == getEventsQueueName(t.Microservice) {
+		// the route of a nacked event with RequeueExpiration, see publishNackEvent
+		exchange, routingKey = string(MatchingRequeueExchange), ""
+		delete(headers, "all-micro")
+		headers["micro"] = m.Queue
+		if m.Handler != "" {
+			headers[EventHeaderKey(event.MicroserviceEvent(m.Handler))] = m.Handler
+		}
+	}
+
+	ctx, cancel := t.publishContext(ctx)
+	defer cancel()
+	err := t.publisher.publish(ctx, exchange, routingKey, amqp.Publishing{
+		// expires right away in the requeue queue, which dead-letters it to the consumer queue
+		Expiration:      "0",
+		Headers:         headers,
+		ContentType:     m.ContentType,
+		ContentEncoding: m.ContentEncoding,
+		Body:            m.Body,
+		DeliveryMode:    amqp.Persistent,
+		AppId:           m.AppID,
+		MessageId:       m.MessageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	publisher, replayed := m.AppID, m.Handler
+	if publisher == "" {
+		publisher = "unknown"
+	}
+	if replayed == "" {
+		replayed = "unknown"
+	}
+	t.publishAudit(&event.AuditReplayedPayload{
+		PublisherMicroservice: publisher,
+		ReplayerMicroservice:  string(t.Microservice),
+		ReplayedEvent:         replayed,
+		ReplayedAt:            uint64(time.Now().UnixMilli()),
+		QueueName:             m.Queue,
+		ParkingReason:         m.Reason,
+		EventID:               m.MessageID,
+	})
+	return nil
+}
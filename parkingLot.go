@@ -0,0 +1,89 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Headers added to the messages stored in a parking lot queue.
+const (
+	ParkingReasonHeader     = "x-parking-reason"
+	ParkingQueueHeader      = "x-parking-queue"
+	ParkingExchangeHeader   = "x-parking-exchange"
+	ParkingRoutingKeyHeader = "x-parking-routing-key"
+)
+
+// Failure headers, added when a message is nacked and kept while it is retried and parked.
+const (
+	// LastErrorHeader is the error of the handler in the last failure, when it is known.
+	LastErrorHeader = "x-last-error"
+	// AttemptsHeader is the number of failed attempts.
+	AttemptsHeader = "x-attempts"
+	// FirstFailureAtHeader and LastFailureAtHeader are the UNIX timestamps in milliseconds of the failures.
+	FirstFailureAtHeader = "x-first-failure-at"
+	LastFailureAtHeader  = "x-last-failure-at"
+	// HandlerHeader is the event or the saga command whose handler failed.
+	HandlerHeader = "x-handler"
+)
+
+// getParkingLotQueue returns the durable queue where the messages of the consumer queue that cannot
+// be processed are stored, to be inspected instead of being lost.
+func getParkingLotQueue(queueName string) string {
+	return fmt.Sprintf("%s_parking_lot", queueName)
+}
+
+// parkUndecodable stores a delivery that cannot be decoded in the parking lot queue of the consumer,
+// retrying it would not help. The reason is the decode error, prefixed with "decode: ".
+func (t *Transactional) parkUndecodable(channel *amqp.Channel, msg *amqp.Delivery, queueName string, cause error) {
+	c := &ConsumeChannel{t: t, channel: channel, msg: msg, queueName: queueName}
+	c.fail(cause)
+	if err := c.exhausted(c.Attempt(), fmt.Sprintf("decode: %v", cause)); err != nil {
+		log.Printf("Error parking the undecodable message of %s: %v", queueName, err)
+	}
+}
+
+// park stores a copy of the message in the parking lot queue of the consumer and acks it.
+// If the copy cannot be stored, the message is requeued.
+func (c *ConsumeChannel) park(reason string) error {
+	headers := amqp.Table{}
+	for k, v := range c.msg.Headers {
+		headers[k] = v
+	}
+	headers[ParkingReasonHeader] = reason
+	headers[ParkingQueueHeader] = c.queueName
+	headers[ParkingExchangeHeader] = c.msg.Exchange
+	headers[ParkingRoutingKeyHeader] = c.msg.RoutingKey
+	if c.handler != "" {
+		headers[HandlerHeader] = c.handler
+	}
+
+	defer c.settle()
+	ctx, cancel := c.t.publishContext(context.Background())
+	defer cancel()
+	err := c.t.publisher.publish(ctx, "", getParkingLotQueue(c.queueName), amqp.Publishing{
+		Headers:         headers,
+		ContentType:     c.msg.ContentType,
+		ContentEncoding: c.msg.ContentEncoding,
+		Body:            c.msg.Body,
+		DeliveryMode:    amqp.Persistent,
+		AppId:           c.msg.AppId,
+		MessageId:       c.msg.MessageId,
+		Timestamp:       time.Now(),
+	})
+	if err != nil {
+		if nackErr := c.channel.Nack(c.msg.DeliveryTag, false, true); nackErr != nil {
+			return fmt.Errorf("error nacking message: %w", nackErr)
+		}
+		return fmt.Errorf("error parking message: %w", err)
+	}
+
+	err = c.channel.Ack(c.msg.DeliveryTag, false)
+	if err != nil {
+		return fmt.Errorf("error acknowledging message: %w", err)
+	}
+	return nil
+}
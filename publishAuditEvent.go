@@ -4,20 +4,26 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"time"
 
 	"github.com/legendaryum-metaverse/saga/event"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-// publishAuditEvent publishes audit events to the direct audit exchange.
-// Uses the event type as routing key for flexible audit event routing.
+// PublishAuditEvent publishes the audit event with the default Transactional, see SetDefault.
+//
+// Deprecated: use Transactional.PublishAuditEvent.
 func PublishAuditEvent(payload event.PayloadEvent) error {
-	channel, err := getSendChannel()
+	t, err := defaultTransactional()
 	if err != nil {
-		return fmt.Errorf("error getting send channel: %w", err)
+		return err
 	}
+	return t.PublishAuditEvent(payload)
+}
 
+// PublishAuditEvent publishes audit events to the direct audit exchange.
+// Uses the event type as routing key for flexible audit event routing.
+// It waits up to Opts.PublishTimeout for the broker.
+func (t *Transactional) PublishAuditEvent(payload event.PayloadEvent) error {
 	// Use the event type as routing key for flexible audit event routing
 	eventType := payload.Type()
 	routingKey := string(eventType) // "audit.received", "audit.processed", or "audit.dead_letter"
@@ -27,17 +33,15 @@ func PublishAuditEvent(payload event.PayloadEvent) error {
 		return fmt.Errorf("failed to marshal audit payload: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := t.publishContext(context.Background())
 	defer cancel()
 
-	err = channel.PublishWithContext(
+	err = t.publisher.publish(
 		ctx,
 		string(AuditExchange), // exchange
 		routingKey,            // routing key
-		false,                 // mandatory
-		false,                 // immediate
 		amqp.Publishing{
-			ContentType:  "application/json",
+			ContentType:  ContentTypeJSON,
 			Body:         body,
 			DeliveryMode: amqp.Persistent, // persistent
 		},
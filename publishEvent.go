@@ -2,9 +2,7 @@ package saga
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,64 +10,40 @@ import (
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-var (
-	sendChannel       *amqp.Channel
-	publishConnection *amqp.Connection
-)
-
-func getPublishConnection() (*amqp.Connection, error) {
-	if publishConnection != nil {
-		if publishConnection.IsClosed() {
-			publishConnection = nil
-			return getPublishConnection()
-		}
-		return publishConnection, nil
-	}
-	if RabbitUri == "" {
-		return nil, fmt.Errorf("RabbitUri is not set")
-	}
-	var err error
-	publishConnection, err = amqp.Dial(RabbitUri)
+// PublishEvent publishes the event with the default Transactional, see SetDefault.
+//
+// Deprecated: use Transactional.PublishEvent.
+func PublishEvent(payload event.PayloadEvent, opts ...PublishOption) error {
+	t, err := defaultTransactional()
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return err
 	}
-	return publishConnection, nil
+	return t.PublishEvent(payload, opts...)
 }
 
-func getSendChannel() (*amqp.Channel, error) {
-	if sendChannel != nil {
-		if sendChannel.IsClosed() {
-			sendChannel = nil
-			return getSendChannel()
-		}
-		return sendChannel, nil
-	}
-	conn, err := getPublishConnection()
-	if err != nil {
-		return nil, err
-	}
-	sendChannel, err = conn.Channel()
-	if err != nil {
-		return nil, fmt.Errorf("failed to open a channel: %w", err)
-	}
-	return sendChannel, nil
+// PublishEvent publishes the event to every microservice subscribed to it, the publisher
+// microservice is the Transactional's one. It waits up to Opts.PublishTimeout for the broker, see
+// PublishEventContext.
+// The payload is validated first, the error matches ErrInvalidPayload and wraps a *ValidationError
+// listing the failing fields; use SkipValidation to publish it as is.
+// It returns once the broker confirms the message, the error matches ErrUnroutable when no
+// microservice is subscribed to the event and ErrNacked when the broker rejects it.
+func (t *Transactional) PublishEvent(payload event.PayloadEvent, opts ...PublishOption) error {
+	return t.PublishEventContext(context.Background(), payload, opts...)
 }
 
-func PublishEvent(payload event.PayloadEvent) error {
-	channel, err := getSendChannel()
-	if err != nil {
-		return fmt.Errorf("error getting send channel: %w", err)
+// PublishEventContext is PublishEvent bounded by the context as well as by Opts.PublishTimeout.
+func (t *Transactional) PublishEventContext(ctx context.Context, payload event.PayloadEvent, opts ...PublishOption) error {
+	if !newPublishOptions(opts).skipValidation {
+		if err := validatePayload(payload); err != nil {
+			return fmt.Errorf("error publishing event %s: %w", payload.Type(), err)
+		}
 	}
 
 	// Generate UUID v7 for event tracking
 	eventID := uuid.Must(uuid.NewV7()).String()
 
-	// Get publisher microservice name from stored config
-	config := GetStoredConfig()
-	if config == nil {
-		return fmt.Errorf("config not initialized - cannot determine publisher microservice")
-	}
-	publisherMicroservice := string(config.Microservice)
+	publisherMicroservice := string(t.Microservice)
 
 	headerEvent := getEventObject(payload.Type())
 	headersArgs := amqp.Table{
@@ -79,30 +53,34 @@ func PublishEvent(payload event.PayloadEvent) error {
 		headersArgs[k] = v
 	}
 
-	body, err := json.Marshal(payload)
+	body, err := t.codec.Marshal(payload)
 	if err != nil {
-		return err
+		return fmt.Errorf("error encoding event %s: %w", payload.Type(), err)
+	}
+	body, contentEncoding, err := t.compress(body)
+	if err != nil {
+		return fmt.Errorf("error compressing event %s: %w", payload.Type(), err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	// the message is mandatory, if no microservice is subscribed to the event the error matches ErrUnroutable
+	ctx, cancel := t.publishContext(ctx)
 	defer cancel()
-	err = channel.PublishWithContext(
+	err = t.publisher.publish(
 		ctx,
 		string(MatchingExchange),
 		"",
-		false, // mandatory
-		false, // immediate
 		amqp.Publishing{
-			Headers:      headersArgs,
-			ContentType:  "application/json",
-			Body:         body,
-			DeliveryMode: amqp.Persistent,
-			MessageId:    eventID,               // UUID v7 for event tracking
-			AppId:        publisherMicroservice, // Publisher microservice name
+			Headers:         headersArgs,
+			ContentType:     t.codec.ContentType(),
+			ContentEncoding: contentEncoding,
+			Body:            body,
+			DeliveryMode:    amqp.Persistent,
+			MessageId:       eventID,               // UUID v7 for event tracking
+			AppId:           publisherMicroservice, // Publisher microservice name
 		},
 	)
 	if err != nil {
-		return fmt.Errorf("error publishing message: %w", err)
+		return fmt.Errorf("error publishing event %s: %w", payload.Type(), err)
 	}
 	timestamp := uint64(time.Now().UnixMilli())
 	auditPayload := event.AuditPublishedPayload{
@@ -112,11 +90,7 @@ func PublishEvent(payload event.PayloadEvent) error {
 		EventID:               eventID,
 	}
 	// Emit audit.published event (fire-and-forget - never fail the main flow)
-	go func() {
-		if auditErr := PublishAuditEvent(&auditPayload); auditErr != nil {
-			log.Printf("Failed to emit audit.published event: %v", auditErr)
-		}
-	}()
+	t.publishAudit(&auditPayload)
 
 	return nil
 }
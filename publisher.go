@@ -0,0 +1,201 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// DefaultPublisherChannels is the default size of the pool of send channels of a Transactional.
+const DefaultPublisherChannels = 8
+
+// DefaultPublishTimeout is the default time a publish waits for the broker confirmation.
+const DefaultPublishTimeout = 5 * time.Second
+
+// returnsBuffer is the capacity of the channel receiving the basic.return of a send channel.
+// It must be buffered: the returns are delivered by the connection reader before the confirmation
+// of the same message, a buffered channel guarantees the return is available once confirmed.
+const returnsBuffer = 16
+
+// sendChannel is a channel in confirm mode of the pool and the returns of its mandatory messages.
+type sendChannel struct {
+	channel *amqp.Channel
+	returns chan amqp.Return
+}
+
+// publisher owns the connection and the pool of channels used by a Transactional to publish messages.
+// They are separated from the consumer connection so that a slow consumer does not block
+// the publishing (and vice versa), and they are opened lazily on the first publish.
+//
+// Every send channel is in confirm mode and every message is published as mandatory, so a publish
+// only succeeds once the broker has routed the message to at least one queue and acked it.
+// A channel is used by one publish at a time, which matches the confirmation and the basic.return
+// with the message, and the concurrent publishes are spread over the pool.
+type publisher struct {
+	rabbitUri string
+
+	// mu guards the connection, which is dialed again when it is closed.
+	mu     sync.Mutex
+	conn   *amqp.Connection
+	closed bool
+
+	// pool bounds the number of send channels, a nil entry is a channel not opened yet
+	// or discarded because it was closed.
+	pool chan *sendChannel
+}
+
+// publishContext bounds the context of a publish with Opts.PublishTimeout.
+func (t *Transactional) publishContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, t.publishTimeout)
+}
+
+func newPublisher(rabbitUri string, size int) *publisher {
+	if size <= 0 {
+		size = DefaultPublisherChannels
+	}
+	pool := make(chan *sendChannel, size)
+	for range size {
+		pool <- nil
+	}
+	return &publisher{rabbitUri: rabbitUri, pool: pool}
+}
+
+// connection returns the publishing connection, dialing it again if it was closed.
+func (p *publisher) connection() (*amqp.Connection, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		return nil, fmt.Errorf("publisher is closed")
+	}
+	if p.conn == nil || p.conn.IsClosed() {
+		conn, err := amqp.Dial(p.rabbitUri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		}
+		p.conn = conn
+	}
+	return p.conn, nil
+}
+
+// acquire takes a send channel from the pool, waiting for one to be released if all are in use.
+// The channel must be given back with release.
+func (p *publisher) acquire(ctx context.Context) (*sendChannel, error) {
+	var sc *sendChannel
+	select {
+	case sc = <-p.pool:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	if sc != nil && !sc.channel.IsClosed() {
+		return sc, nil
+	}
+
+	conn, err := p.connection()
+	if err != nil {
+		p.pool <- nil
+		return nil, err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		p.pool <- nil
+		return nil, fmt.Errorf("failed to open a channel: %w", err)
+	}
+	if err = channel.Confirm(false); err != nil {
+		_ = channel.Close()
+		p.pool <- nil
+		return nil, fmt.Errorf("failed to put the channel in confirm mode: %w", err)
+	}
+	return &sendChannel{
+		channel: channel,
+		returns: channel.NotifyReturn(make(chan amqp.Return, returnsBuffer)),
+	}, nil
+}
+
+func (p *publisher) release(sc *sendChannel) {
+	if sc.channel.IsClosed() {
+		sc = nil
+	}
+	p.pool <- sc
+}
+
+// publish publishes the message as mandatory and waits for the broker confirmation.
+// The error matches ErrUnroutable when the message did not reach any queue and ErrNacked
+// when the broker nacked it; if the context is done before the confirmation, the context
+// error is returned and the message may or may not have been delivered.
+func (p *publisher) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	return p.publishWith(ctx, exchange, routingKey, true, msg)
+}
+
+// publishWith is publish with the mandatory flag, a message that is not mandatory is confirmed even
+// when it reaches no queue.
+func (p *publisher) publishWith(ctx context.Context, exchange, routingKey string, mandatory bool, msg amqp.Publishing) error {
+	if msg.MessageId == "" {
+		// the message id matches the basic.return with the message
+		msg.MessageId = uuid.Must(uuid.NewV7()).String()
+	}
+
+	sc, err := p.acquire(ctx)
+	if err != nil {
+		return newError(ErrBrokerUnavailable, "error getting send channel", err)
+	}
+	defer p.release(sc)
+	sc.returnOf(msg.MessageId)
+
+	confirmation, err := sc.channel.PublishWithDeferredConfirmWithContext(ctx, exchange, routingKey, mandatory, false, msg)
+	if err != nil {
+		return fmt.Errorf("error publishing message: %w", err)
+	}
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("error waiting for the broker confirmation: %w", err)
+	}
+
+	if r, ok := sc.returnOf(msg.MessageId); ok {
+		return newError(ErrUnroutable, fmt.Sprintf("message %s to exchange %q with routing key %q was returned", msg.MessageId, exchange, routingKey),
+			fmt.Errorf("%d %s", r.ReplyCode, r.ReplyText))
+	}
+	if !acked {
+		return newError(ErrNacked, fmt.Sprintf("message %s to exchange %q with routing key %q", msg.MessageId, exchange, routingKey),
+			fmt.Errorf("the broker nacked the message"))
+	}
+	return nil
+}
+
+// returnOf empties the buffered returns and reports the one of the message, the others belong to
+// messages whose publisher stopped waiting for the confirmation.
+func (sc *sendChannel) returnOf(messageId string) (amqp.Return, bool) {
+	var (
+		returned amqp.Return
+		found    bool
+	)
+	for {
+		select {
+		case r, ok := <-sc.returns:
+			if !ok {
+				// the channel was closed
+				return returned, found
+			}
+			if r.MessageId == messageId {
+				returned, found = r, true
+			}
+		default:
+			return returned, found
+		}
+	}
+}
+
+func (p *publisher) close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	if p.conn == nil || p.conn.IsClosed() {
+		return nil
+	}
+	return p.conn.Close()
+}
@@ -0,0 +1,259 @@
+package saga
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ConnectionStatus describes the state of the consumer connection of a Transactional.
+type ConnectionStatus string
+
+const (
+	StatusConnected       ConnectionStatus = "connected"
+	StatusDisconnected    ConnectionStatus = "disconnected"
+	StatusReconnecting    ConnectionStatus = "reconnecting"
+	StatusReconnectFailed ConnectionStatus = "reconnect_failed"
+	StatusClosed          ConnectionStatus = "closed"
+)
+
+// DefaultMaxReconnectDelay caps the fibonacci backoff between reconnection attempts.
+const DefaultMaxReconnectDelay = 30 * time.Second
+
+// ReconnectEvent is reported to Opts.OnReconnect every time the connection status changes.
+//   - StatusDisconnected: the broker closed the connection, Err holds the reason.
+//   - StatusReconnecting: attempt number Attempt will be made after waiting Delay.
+//   - StatusReconnectFailed: attempt number Attempt failed with Err, another one will follow.
+//   - StatusConnected: attempt number Attempt succeeded and the consumers are resumed.
+type ReconnectEvent struct {
+	Status  ConnectionStatus
+	Attempt int
+	Delay   time.Duration
+	Err     error
+}
+
+// Status returns the current status of the consumer connection.
+func (t *Transactional) Status() ConnectionStatus {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.status
+}
+
+func (t *Transactional) setStatus(ev ReconnectEvent) {
+	t.mu.Lock()
+	t.status = ev.Status
+	t.isConnected = ev.Status == StatusConnected
+	t.mu.Unlock()
+
+	if t.onReconnect != nil {
+		t.onReconnect(ev)
+	}
+}
+
+func (t *Transactional) connection() *amqp.Connection {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.conn
+}
+
+func (t *Transactional) isClosing() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// errConnectionClosed is the reason of a connection closed without an error from the broker.
+var errConnectionClosed = errors.New("connection closed")
+
+// lostResource reports to supervise that the connection, or the consumer of a channel, stopped.
+type lostResource struct {
+	// conn is the connection that was closed, with err as the reason.
+	conn *amqp.Connection
+	err  error
+	// channel is the channel whose consumer stopped, resume starts it again.
+	channel *amqp.Channel
+	name    string
+	resume  func(*amqp.Channel) error
+}
+
+// report hands the lost resource to supervise, unless the Transactional is stopped.
+func (t *Transactional) report(l lostResource) {
+	select {
+	case t.lost <- l:
+	case <-t.done:
+	}
+}
+
+// supervise reconnects the lost connections and resumes the stopped consumers, one at a time, until
+// the Transactional is stopped.
+func (t *Transactional) supervise() {
+	for {
+		select {
+		case <-t.done:
+			return
+		case l := <-t.lost:
+			if l.channel != nil {
+				t.resumeConsumer(l)
+				continue
+			}
+			if l.conn != t.connection() {
+				// a connection replaced by a reconnection that was already in progress
+				continue
+			}
+			t.setStatus(ReconnectEvent{Status: StatusDisconnected, Err: l.err})
+			t.reconnect()
+		}
+	}
+}
+
+func (t *Transactional) notifyClose(conn *amqp.Connection) {
+	closeReceiver := conn.NotifyClose(make(chan *amqp.Error, 1))
+
+	go func() {
+		// the receiver is closed without an error when the connection is closed on purpose, which
+		// is only a loss when the Transactional is not stopping, see Shutdown.
+		var err error = errConnectionClosed
+		if amqpErr, ok := <-closeReceiver; ok && amqpErr != nil {
+			err = amqpErr
+		}
+		if t.isClosing() {
+			return
+		}
+		t.report(lostResource{conn: conn, err: err})
+	}()
+}
+
+// watchChannel logs why the consumer of a new channel stops: the broker closed the channel, or
+// cancelled the consumer, like when its queue is deleted.
+func watchChannel(ch *amqp.Channel, name string) {
+	closed := ch.NotifyClose(make(chan *amqp.Error, 1))
+	cancelled := ch.NotifyCancel(make(chan string, 1))
+
+	go func() {
+		// both are closed with the channel
+		for tag := range cancelled {
+			log.Printf("The %s consumer %s was cancelled by the broker", name, tag)
+		}
+	}()
+	go func() {
+		if amqpErr, ok := <-closed; ok && amqpErr != nil {
+			log.Printf("The %s channel was closed: %v", name, amqpErr)
+		}
+	}()
+}
+
+// notifyStopped returns the function that reports the consumer of the channel once its deliveries
+// end, unless the Transactional is stopping.
+func (t *Transactional) notifyStopped(ch *amqp.Channel, name string, resume func(*amqp.Channel) error) func() {
+	return func() {
+		if t.isClosing() {
+			return
+		}
+		t.report(lostResource{channel: ch, name: name, resume: resume})
+	}
+}
+
+// resumeConsumer consumes again on the channel of a stopped consumer, or on a new one if the channel
+// was closed, waiting a fibonacci backoff between attempts. A consumer stopped with its connection is
+// resumed by the reconnection instead.
+func (t *Transactional) resumeConsumer(l lostResource) {
+	for attempt := 1; ; attempt++ {
+		t.mu.Lock()
+		current := l.channel == t.eventsChannel || l.channel == t.sagaChannel
+		conn := t.conn
+		t.mu.Unlock()
+		if !current || conn == nil || conn.IsClosed() || t.isClosing() {
+			return
+		}
+
+		err := l.resume(l.channel)
+		if err == nil {
+			log.Printf("The %s consumer was resumed", l.name)
+			return
+		}
+		log.Printf("Attempt %d to resume the %s consumer failed: %v", attempt, l.name, err)
+
+		select {
+		case <-t.done:
+			return
+		case <-time.After(reconnectDelay(attempt, t.maxReconnectDelay)):
+		}
+	}
+}
+
+// reconnect dials the broker until it succeeds or the Transactional is stopped, waiting a
+// fibonacci backoff between attempts. Once connected, every consumer that was started
+// with ConnectToEvents or ConnectToSagaCommandEmitter is resumed on the same Emitter, so the
+// registered handlers keep receiving messages.
+func (t *Transactional) reconnect() {
+	for attempt := 1; ; attempt++ {
+		delay := reconnectDelay(attempt, t.maxReconnectDelay)
+		t.setStatus(ReconnectEvent{Status: StatusReconnecting, Attempt: attempt, Delay: delay})
+
+		select {
+		case <-t.done:
+			return
+		case <-time.After(delay):
+		}
+
+		if err := t.connect(); err != nil {
+			log.Printf("Reconnection attempt %d to RabbitMQ failed: %v", attempt, err)
+			t.setStatus(ReconnectEvent{Status: StatusReconnectFailed, Attempt: attempt, Err: err})
+			continue
+		}
+		t.setStatus(ReconnectEvent{Status: StatusConnected, Attempt: attempt})
+		return
+	}
+}
+
+// connect dials a new connection and resumes the consumers that were already started.
+func (t *Transactional) connect() error {
+	conn, err := amqp.Dial(t.rabbitUri)
+	if err != nil {
+		return newError(ErrBrokerUnavailable, "Failed to connect to RabbitMQ", err)
+	}
+
+	t.mu.Lock()
+	t.conn = conn
+	eventsEmitter := t.eventsEmitter
+	sagaEmitter := t.sagaEmitter
+	t.mu.Unlock()
+	// watched before the consumers start, so that a connection lost while they start is reconnected
+	t.notifyClose(conn)
+
+	if eventsEmitter != nil {
+		if err = t.consumeEvents(eventsEmitter, nil); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+	if sagaEmitter != nil {
+		if err = t.consumeSagaCommands(sagaEmitter, nil); err != nil {
+			_ = conn.Close()
+			return err
+		}
+	}
+
+	if t.isClosing() {
+		// StopRabbitMQ was called while reconnecting.
+		return conn.Close()
+	}
+	return nil
+}
+
+func reconnectDelay(attempt int, maxDelay time.Duration) time.Duration {
+	if attempt > MAX_OCCURRENCE {
+		// avoid overflowing the fibonacci sequence on long outages
+		return maxDelay
+	}
+	delay := time.Duration(fibonacci(attempt)) * time.Second
+	if delay > maxDelay {
+		return maxDelay
+	}
+	return delay
+}
@@ -0,0 +1,74 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// PanicStrategy decides what happens to a message whose handler panicked.
+type PanicStrategy string
+
+const (
+	// PanicRetryFibonacci nacks the message with NackWithFibonacciStrategy(MAX_OCCURRENCE, MAX_NACK_RETRIES).
+	// It is the default strategy.
+	PanicRetryFibonacci PanicStrategy = "fibonacci"
+	// PanicRetryDelay nacks the message with NackWithDelay(NACKING_DELAY_MS, MAX_NACK_RETRIES).
+	PanicRetryDelay PanicStrategy = "delay"
+	// PanicParkingLot stores the message in the parking lot queue of the consumer.
+	PanicParkingLot PanicStrategy = "parking_lot"
+	// PanicRetryPolicy retries the message with the RetryPolicy of its event or saga command.
+	PanicRetryPolicy PanicStrategy = "policy"
+)
+
+// recovered settles the message of a handler that panicked, unless the handler already did it.
+// It returns the retry count of the nack, zero if the message was parked.
+func (c *ConsumeChannel) recovered(reason string) (int32, error) {
+	if c.isSettled() {
+		return 0, nil
+	}
+	c.fail(errors.New(reason))
+	switch c.t.panicStrategy {
+	case PanicParkingLot:
+		return 0, c.park(reason)
+	case PanicRetryPolicy:
+		count, _, err := c.Retry(c.lastErr)
+		return count, err
+	case PanicRetryDelay:
+		count, _, err := c.NackWithDelay(NACKING_DELAY_MS*time.Millisecond, MAX_NACK_RETRIES)
+		return count, err
+	default:
+		count, _, _, err := c.NackWithFibonacciStrategy(MAX_OCCURRENCE, MAX_NACK_RETRIES)
+		return count, err
+	}
+}
+
+// eventPanic keeps the events consumer alive when a handler panics: the message is nacked with the
+// Opts.PanicStrategy and an audit.dead_letter event is emitted with the panic as rejection reason.
+func (t *Transactional) eventPanic(handler EventHandler, r any, stack []byte) {
+	reason := fmt.Sprintf("panic: %v", r)
+	log.Printf("Recovered from %s in the handler of %s: %s", reason, handler.Channel.eventType, stack)
+
+	settled := handler.Channel.isSettled()
+	count, err := handler.Channel.recovered(reason)
+	if err != nil {
+		log.Printf("Error settling message of %s after panic: %v", handler.Channel.eventType, err)
+	}
+	if settled {
+		return
+	}
+	rc := uint32(count)
+	handler.Channel.publishDeadLetter(reason, &rc)
+}
+
+// commandPanic keeps the saga commands consumer alive when a handler panics: the message is nacked
+// with the Opts.PanicStrategy.
+func (t *Transactional) commandPanic(handler CommandHandler, r any, stack []byte) {
+	reason := fmt.Sprintf("panic: %v", r)
+	log.Printf("Recovered from %s in the handler of %s: %s", reason, handler.Channel.step.Command, stack)
+
+	if _, err := handler.Channel.recovered(reason); err != nil {
+		log.Printf("Error settling message of %s after panic: %v", handler.Channel.step.Command, err)
+	}
+}
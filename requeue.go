@@ -0,0 +1,151 @@
+package saga
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RequeueStrategy is how a nacked message waits for its retry before returning to its consumer queue.
+type RequeueStrategy string
+
+const (
+	// RequeueDelayBuckets publishes the message to the delay queue of its consumer queue whose TTL is
+	// the closest to the delay, see DelayBucket. The messages of a delay queue all wait the same time,
+	// so a long delay never blocks a shorter one, but the delays between two buckets and the
+	// RetryPolicy.Jitter are lost: the first fibonacci delays, 1s, 1s and 2s, all wait 1s. It is the
+	// default strategy.
+	RequeueDelayBuckets RequeueStrategy = "delay_buckets"
+	// RequeueDelayedExchange publishes the message to the DelayedRequeueExchange with the exact delay
+	// in the x-delay header, it needs the rabbitmq_delayed_message_exchange plugin.
+	RequeueDelayedExchange RequeueStrategy = "delayed_exchange"
+	// RequeueExpiration publishes the message with the delay as its expiration to the single requeue
+	// queue of its consumer queue. RabbitMQ only expires the message at the head of a queue, so a
+	// message waits for the ones with longer delays requeued before it.
+	RequeueExpiration RequeueStrategy = "expiration"
+)
+
+// DefaultDelayBuckets are the TTLs of the delay queues of RequeueDelayBuckets.
+var DefaultDelayBuckets = []time.Duration{
+	time.Second,
+	5 * time.Second,
+	30 * time.Second,
+	2 * time.Minute,
+	10 * time.Minute,
+	time.Hour,
+	6 * time.Hour,
+}
+
+// DelayBucket rounds the delay to the closest of the buckets, in proportion to the delay: 2s rounds to
+// 1s rather than to 5s, 4s to 5s. The delays out of the buckets round to the shortest or the longest one.
+// The buckets must be sorted, DefaultDelayBuckets if empty.
+func DelayBucket(buckets []time.Duration, delay time.Duration) time.Duration {
+	if len(buckets) == 0 {
+		buckets = DefaultDelayBuckets
+	}
+	i, found := slices.BinarySearch(buckets, delay)
+	switch {
+	case found:
+		return buckets[i]
+	case i == 0:
+		return buckets[0]
+	case i == len(buckets):
+		return buckets[len(buckets)-1]
+	}
+	shorter, longer := buckets[i-1], buckets[i]
+	if math.Log(float64(delay)/float64(shorter)) <= math.Log(float64(longer)/float64(delay)) {
+		return shorter
+	}
+	return longer
+}
+
+// getDelayQueue returns the delay queue of the consumer queue whose messages wait for the bucket,
+// named after its TTL in milliseconds.
+func getDelayQueue(queueName string, bucket time.Duration) string {
+	return fmt.Sprintf("%s_delay_%d", queueName, bucket.Milliseconds())
+}
+
+// createRequeue declares the resources that bring the nacked messages back to the consumer queue
+// with the requeue strategy.
+// The delay queues dead-letter their messages to the consumer queue through the default exchange, so
+// that they reach the microservice that nacked them only.
+func (t *Transactional) createRequeue(ch *amqp.Channel, queueName string) error {
+	switch t.requeue {
+	case RequeueDelayedExchange:
+		err := ch.ExchangeDeclare(string(DelayedRequeueExchange), "x-delayed-message", true, false, false, false, amqp.Table{
+			"x-delayed-type": "direct",
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare exchange %s, is the rabbitmq_delayed_message_exchange plugin enabled?: %w", DelayedRequeueExchange, err)
+		}
+		err = ch.QueueBind(queueName, queueName, string(DelayedRequeueExchange), false, nil)
+		if err != nil {
+			return fmt.Errorf("failed to bind queue %s to exchange %s: %w", queueName, DelayedRequeueExchange, err)
+		}
+	case RequeueDelayBuckets:
+		for _, bucket := range t.delayBuckets {
+			delayQueue := getDelayQueue(queueName, bucket)
+			_, err := ch.QueueDeclare(delayQueue, true, false, false, false, amqp.Table{
+				"x-message-ttl":             bucket.Milliseconds(),
+				"x-dead-letter-exchange":    "",
+				"x-dead-letter-routing-key": queueName,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to declare delay queue %s: %w", delayQueue, err)
+			}
+		}
+	}
+	return nil
+}
+
+// publishNackEvent publishes the copy of the nacked message that waits for the delay with the requeue
+// strategy and waits for the broker confirmation, like park. It returns the delay the copy actually waits.
+func (c *ConsumeChannel) publishNackEvent(delay time.Duration) (time.Duration, error) {
+	ctx, cancel := c.t.publishContext(context.Background())
+	defer cancel()
+	// the delayed exchange routes the message once the delay is over, it cannot be mandatory
+	mandatory := c.t.requeue != RequeueDelayedExchange
+	msg := amqp.Publishing{
+		Headers:         c.msg.Headers,
+		Body:            c.msg.Body,
+		ContentType:     c.msg.ContentType,
+		ContentEncoding: c.msg.ContentEncoding,
+		DeliveryMode:    amqp.Persistent,
+		AppId:           c.msg.AppId,
+		MessageId:       c.msg.MessageId,
+	}
+
+	var exchange, routingKey string
+	switch c.t.requeue {
+	case RequeueDelayedExchange:
+		exchange, routingKey = string(DelayedRequeueExchange), c.queueName
+		msg.Headers[DelayHeader] = delay.Milliseconds()
+	case RequeueExpiration:
+		msg.Expiration = fmt.Sprintf("%d", delay.Milliseconds())
+		// the deliveries of the other strategies come from the default exchange, the queue tells the
+		// events from the saga commands
+		if c.queueName == getEventsQueueName(c.t.Microservice) {
+			// the header that is deleted is the one that has all the micros listening to a certain event,
+			// otherwise the nacking reaches everyone.
+			delete(c.msg.Headers, "all-micro")
+			// deliver to one micro in particular, the one that is nacking
+			c.msg.Headers["micro"] = c.queueName
+			exchange = string(MatchingRequeueExchange)
+		} else {
+			exchange, routingKey = string(RequeueExchange), fmt.Sprintf("%s_routing_key", c.queueName)
+		}
+	default:
+		delay = DelayBucket(c.t.delayBuckets, delay)
+		routingKey = getDelayQueue(c.queueName, delay)
+	}
+
+	err := c.t.publisher.publishWith(ctx, exchange, routingKey, mandatory, msg)
+	if err != nil {
+		return 0, fmt.Errorf("error publishing message: %w", err)
+	}
+	return delay, nil
+}
@@ -11,6 +11,8 @@ const (
 	MatchingExchange        Exchange = "matching_exchange"
 	MatchingRequeueExchange Exchange = "matching_requeue_exchange"
 	AuditExchange           Exchange = "audit_exchange"
+	// DelayedRequeueExchange is the x-delayed-message exchange of RequeueDelayedExchange.
+	DelayedRequeueExchange Exchange = "delayed_requeue_exchange"
 )
 
 const (
@@ -19,4 +21,5 @@ const (
 	AuditReceivedCommandsQ   Queue = "audit_received_commands"
 	AuditProcessedCommandsQ  Queue = "audit_processed_commands"
 	AuditDeadLetterCommandsQ Queue = "audit_dead_letter_commands"
+	AuditReplayedCommandsQ   Queue = "audit_replayed_commands"
 )
@@ -0,0 +1,139 @@
+package saga
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/legendaryum-metaverse/saga/micro"
+)
+
+// Backoff is how the delay between the retries of a RetryPolicy grows.
+type Backoff string
+
+const (
+	// BackoffFixed waits Delay between the retries.
+	BackoffFixed Backoff = "fixed"
+	// BackoffExponential waits Delay*Multiplier^(attempt-1).
+	BackoffExponential Backoff = "exponential"
+	// BackoffFibonacci waits Delay*fibonacci(attempt), it is the default backoff.
+	BackoffFibonacci Backoff = "fibonacci"
+)
+
+// RetryPolicy decides how a message whose handler failed is retried, see ConsumeChannel.Retry.
+// The zero value retries with the fibonacci backoff in seconds up to MAX_NACK_RETRIES times.
+type RetryPolicy struct {
+	// Backoff is BackoffFibonacci if empty.
+	Backoff Backoff `validate:"omitempty,oneof=fixed exponential fibonacci"`
+	// Delay is the delay of BackoffFixed, NACKING_DELAY_MS if zero, and the base of the other
+	// backoffs, a second if zero.
+	Delay time.Duration `validate:"gte=0"`
+	// MaxDelay caps the delay of a retry, uncapped if zero.
+	MaxDelay time.Duration `validate:"gte=0"`
+	// Multiplier is the growth of BackoffExponential, 2 if zero.
+	Multiplier float64 `validate:"omitempty,gt=1"`
+	// Jitter randomizes the delays by up to this fraction of them, from 0 to 1, so that the messages
+	// failing together are not retried together. It only takes effect with RequeueDelayedExchange and
+	// RequeueExpiration, RequeueDelayBuckets rounds the jittered delays back to the same bucket.
+	Jitter float64 `validate:"gte=0,lte=1"`
+	// MaxAttempts is the number of retries before the message is parked, MAX_NACK_RETRIES if zero.
+	MaxAttempts int32 `validate:"gte=0"`
+	// MaxAge is the time from the first failure after which the message is parked instead of
+	// retried, unlimited if zero.
+	MaxAge time.Duration `validate:"gte=0"`
+}
+
+// withDefaults returns the policy with the defaults of the zero fields.
+func (p RetryPolicy) withDefaults() RetryPolicy {
+	if p.Backoff == "" {
+		p.Backoff = BackoffFibonacci
+	}
+	if p.Delay == 0 {
+		p.Delay = time.Second
+		if p.Backoff == BackoffFixed {
+			p.Delay = NACKING_DELAY_MS * time.Millisecond
+		}
+	}
+	if p.Multiplier == 0 {
+		p.Multiplier = 2
+	}
+	if p.MaxAttempts == 0 {
+		p.MaxAttempts = MAX_NACK_RETRIES
+	}
+	return p
+}
+
+// DelayFor returns the delay before the retry of the attempt, starting at 1, with the jitter applied.
+func (p RetryPolicy) DelayFor(attempt int32) time.Duration {
+	p = p.withDefaults()
+	attempt = max(attempt, 1)
+	var delay float64
+	switch p.Backoff {
+	case BackoffFixed:
+		delay = float64(p.Delay)
+	case BackoffExponential:
+		delay = float64(p.Delay) * math.Pow(p.Multiplier, float64(attempt-1))
+	default:
+		delay = float64(p.Delay) * float64(fibonacci(int(attempt)))
+	}
+	if p.Jitter > 0 {
+		delay *= 1 - p.Jitter + 2*p.Jitter*rand.Float64()
+	}
+	if p.MaxDelay > 0 && delay > float64(p.MaxDelay) {
+		return p.MaxDelay
+	}
+	if delay > math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// eventRetryPolicy and commandRetryPolicy return the policy of the handler, the one of
+// Opts.EventRetryPolicy or Opts.CommandRetryPolicy if set, Opts.RetryPolicy otherwise.
+func (t *Transactional) eventRetryPolicy(ev event.MicroserviceEvent) RetryPolicy {
+	if p, ok := t.eventRetryPolicies[ev]; ok {
+		return p.withDefaults()
+	}
+	return t.retryPolicy.withDefaults()
+}
+
+func (t *Transactional) commandRetryPolicy(command micro.StepCommand) RetryPolicy {
+	if p, ok := t.commandRetryPolicies[command]; ok {
+		return p.withDefaults()
+	}
+	return t.retryPolicy.withDefaults()
+}
+
+// Retry nacks the message with the RetryPolicy of its event or saga command, err is the failure of
+// the handler, stored in the failure headers. Once the policy's MaxAttempts or MaxAge is exceeded,
+// the message is stored in the parking lot queue of the consumer instead.
+// It returns the retry count and the delay of the retry, rounded by the requeue strategy, zero if
+// the message was parked.
+func (c *ConsumeChannel) Retry(err error) (int32, time.Duration, error) {
+	count, delay, _, retryErr := c.retry(err)
+	return count, delay, retryErr
+}
+
+// retry applies the retry policy and returns the retry count, the delay and the reason of the outcome.
+func (c *ConsumeChannel) retry(err error) (int32, time.Duration, string, error) {
+	c.fail(err)
+	p := c.policy.withDefaults()
+
+	count := c.Attempt()
+
+	policy := string(p.Backoff) + "_policy"
+	if count > p.MaxAttempts {
+		reason := fmt.Sprintf("%s: max attempts reached: %d", policy, p.MaxAttempts)
+		return count, 0, reason, c.exhausted(count, reason)
+	}
+	if first, ok := c.FirstFailureAt(); ok && p.MaxAge > 0 && time.Since(first) > p.MaxAge {
+		reason := fmt.Sprintf("%s: max age reached: %s", policy, p.MaxAge)
+		return count, 0, reason, c.exhausted(count, reason)
+	}
+
+	delay, err := c.retryIn(count, p.DelayFor(count))
+	reason := fmt.Sprintf("%s: attempt %d of %d in %s", policy, count, p.MaxAttempts, delay)
+	return count, delay, reason, err
+}
@@ -1,6 +1,10 @@
 package saga
 
-import "github.com/legendaryum-metaverse/saga/micro"
+import (
+	"encoding/json"
+
+	"github.com/legendaryum-metaverse/saga/micro"
+)
 
 type Status string
 
@@ -20,3 +24,15 @@ type SagaStep struct {
 	PreviousPayload map[string]interface{}       `json:"previousPayload"`
 	IsCurrentStep   bool                         `json:"isCurrentStep"`
 }
+
+// rawSagaStep is the SagaStep as consumed, the payloads are kept as JSON and only decoded by the
+// handlers that need them.
+type rawSagaStep struct {
+	Microservice    micro.AvailableMicroservices `json:"microservice"`
+	Command         string                       `json:"command"`
+	Status          Status                       `json:"status"`
+	SagaID          int                          `json:"sagaId"`
+	Payload         json.RawMessage              `json:"payload"`
+	PreviousPayload json.RawMessage              `json:"previousPayload"`
+	IsCurrentStep   bool                         `json:"isCurrentStep"`
+}
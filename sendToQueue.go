@@ -3,31 +3,37 @@ package saga
 import (
 	"context"
 	"encoding/json"
-	"time"
 
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
-func (m *MicroserviceConsumeChannel) sendToQueue(queueName Queue, step SagaStep) error {
-	err := send(m.channel, string(queueName), step)
+func (m *MicroserviceConsumeChannel) sendToQueue(queueName Queue, step rawSagaStep) error {
+	err := m.t.send(context.Background(), string(queueName), step)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func send(channel *amqp.Channel, queueName string, payload interface{}) error {
+// send publishes the payload directly to the queue and waits up to Opts.PublishTimeout for the
+// broker confirmation, the error matches ErrUnroutable when the queue does not exist.
+func (t *Transactional) send(ctx context.Context, queueName string, payload interface{}) error {
 	body, err := json.Marshal(payload)
 	if err != nil {
 		return err
 	}
+	body, contentEncoding, err := t.compress(body)
+	if err != nil {
+		return err
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := t.publishContext(ctx)
 	defer cancel()
-	err = channel.PublishWithContext(ctx, "", queueName, false, false, amqp.Publishing{
-		DeliveryMode: amqp.Persistent,
-		ContentType:  "application/json",
-		Body:         body,
+	err = t.publisher.publish(ctx, "", queueName, amqp.Publishing{
+		DeliveryMode:    amqp.Persistent,
+		ContentType:     ContentTypeJSON,
+		ContentEncoding: contentEncoding,
+		Body:            body,
 	})
 	if err != nil {
 		return err
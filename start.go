@@ -1,9 +1,18 @@
 package saga
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
 
 	"github.com/legendaryum-metaverse/saga/event"
 	"github.com/legendaryum-metaverse/saga/micro"
@@ -14,6 +23,10 @@ func getQueueName(microservice micro.AvailableMicroservices) string {
 	return fmt.Sprintf("%s_saga_commands", microservice)
 }
 
+func getEventsQueueName(microservice micro.AvailableMicroservices) string {
+	return fmt.Sprintf("%s_match_commands", microservice)
+}
+
 func getQueueConsumer(microservice micro.AvailableMicroservices) QueueConsumerProps {
 	return QueueConsumerProps{
 		QueueName: getQueueName(microservice),
@@ -25,26 +38,98 @@ type Transactional struct {
 	Microservice micro.AvailableMicroservices
 	Events       []event.MicroserviceEvent
 
+	rabbitUri string
+	// mu guards the connection, the consumer channels and the emitters, which are replaced
+	// every time the connection is re-established.
+	mu            sync.Mutex
 	conn          *amqp.Connection
 	eventsChannel *amqp.Channel
 	sagaChannel   *amqp.Channel
 	isConnected   bool
-	// healthCheckQueue is the queue to check if the rabbitmq connection is alive.
+	status        ConnectionStatus
+	// eventsEmitter and sagaEmitter are kept to resume the delivery into them after a reconnection.
+	eventsEmitter *Emitter[EventHandler, event.MicroserviceEvent]
+	sagaEmitter   *Emitter[CommandHandler, micro.StepCommand]
+	// done is closed by Shutdown to stop any reconnection in progress, lost reports the lost
+	// connections and the stopped consumers to supervise.
+	done              chan struct{}
+	lost              chan lostResource
+	stopOnce          sync.Once
+	onReconnect       func(ReconnectEvent)
+	maxReconnectDelay time.Duration
+	// ctx is handed to the handlers and cancelled when the shutdown begins.
+	ctx               context.Context
+	cancel            context.CancelFunc
+	shuttingDown      bool
+	shutdownTimeout   time.Duration
+	eventsConsumerTag string
+	sagaConsumerTag   string
+	// inflight counts the deliveries handed to a handler and not yet acked or nacked.
+	inflight tracker
+	// audits counts the audit events being published in background.
+	audits tracker
+	// publisher owns the connection used by PublishEvent, PublishAuditEvent and CommenceSaga.
+	publisher          *publisher
+	publishTimeout     time.Duration
+	prefetch           int
+	concurrency        int
+	eventConcurrency   map[event.MicroserviceEvent]int
+	commandConcurrency map[micro.StepCommand]int
+	unhandled          UnhandledPolicy
+	handlerGracePeriod time.Duration
+	requireHandlers    bool
+	panicStrategy      PanicStrategy
+	// codec encodes the published events, codecs decode the deliveries by content type.
+	codec  Codec
+	codecs map[string]Codec
+	// compression of the published payloads from compressionThreshold bytes, maxDecompressedSize
+	// guards the consumers.
+	compression          Compression
+	compressionThreshold int
+	maxDecompressedSize  int64
+	// retryPolicy is the default RetryPolicy, overridden by eventRetryPolicies and commandRetryPolicies.
+	retryPolicy          RetryPolicy
+	eventRetryPolicies   map[event.MicroserviceEvent]RetryPolicy
+	commandRetryPolicies map[micro.StepCommand]RetryPolicy
+	// requeue is how the nacked messages wait for their retry, with the sorted delayBuckets.
+	requeue      RequeueStrategy
+	delayBuckets []time.Duration
+	// healthCheckQueue is the queue to check if the rabbitmq connection is alive, guarded by mu.
 	// This queue is set in the creation of resources, consumers, queues, and exchanges.
 	healthCheckQueue string
 }
 
 var (
-	validate     *validator.Validate
-	storedConfig *Transactional
+	validate *validator.Validate
+	// storedConfig is the default Transactional used by the deprecated package level publish functions.
+	storedConfig   *Transactional
+	storedConfigMu sync.Mutex
 )
 
-// GetStoredConfig returns the stored Transactional configuration.
-// This is used by publishEvent to access the current microservice name.
+// GetStoredConfig returns the default Transactional, see SetDefault.
 func GetStoredConfig() *Transactional {
+	storedConfigMu.Lock()
+	defer storedConfigMu.Unlock()
 	return storedConfig
 }
 
+// SetDefault sets the Transactional used by the deprecated package level functions
+// PublishEvent, PublishAuditEvent and CommenceSaga.
+// Config sets the first Transactional it creates as the default, NewTransactional never does.
+func SetDefault(t *Transactional) {
+	storedConfigMu.Lock()
+	defer storedConfigMu.Unlock()
+	storedConfig = t
+}
+
+func defaultTransactional() (*Transactional, error) {
+	t := GetStoredConfig()
+	if t == nil {
+		return nil, fmt.Errorf("config not initialized - cannot determine publisher microservice")
+	}
+	return t, nil
+}
+
 func init() {
 	validate = validator.New()
 	err := validate.RegisterValidation("microservice", func(fl validator.FieldLevel) bool {
@@ -54,21 +139,135 @@ func init() {
 	if err != nil {
 		panic(err)
 	}
+	err = validate.RegisterValidation("event", func(fl validator.FieldLevel) bool {
+		return event.IsRegistered(event.MicroserviceEvent(fl.Field().String()))
+	})
+	if err != nil {
+		panic(err)
+	}
+	// the fields of the payloads are reported with their JSON names
+	validate.RegisterTagNameFunc(func(fld reflect.StructField) string {
+		name, _, _ := strings.Cut(fld.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
 }
 
 type Opts struct {
 	RabbitUri    string                       `validate:"required,url"`
 	Microservice micro.AvailableMicroservices `validate:"required,microservice"`
-	Events       []event.MicroserviceEvent    `validate:"-"`
+	Events       []event.MicroserviceEvent    `validate:"dive,event"`
+	// OnReconnect is called every time the status of the consumer connection changes,
+	// see ReconnectEvent. It is called synchronously from the reconnection loop.
+	OnReconnect func(ReconnectEvent) `validate:"-"`
+	// MaxReconnectDelay caps the backoff between reconnection attempts, DefaultMaxReconnectDelay if zero.
+	MaxReconnectDelay time.Duration `validate:"-"`
+	// ShutdownTimeout is how long Run and StopRabbitMQ wait for the in-flight messages to be
+	// settled, DefaultShutdownTimeout if zero.
+	ShutdownTimeout time.Duration `validate:"-"`
+	// PublisherChannels is the size of the pool of channels used to publish concurrently,
+	// DefaultPublisherChannels if zero.
+	PublisherChannels int `validate:"-"`
+	// PublishTimeout is how long PublishEvent, PublishAuditEvent, CommenceSaga, the replies of the
+	// saga steps, and the messages parked, retried or replayed wait for the broker confirmation,
+	// DefaultPublishTimeout if zero.
+	PublishTimeout time.Duration `validate:"-"`
+	// Prefetch is the number of unacked messages delivered to each consumer, DefaultPrefetch if zero.
+	Prefetch int `validate:"-"`
+	// Concurrency is the number of messages of the same event or command handled at the same time,
+	// DefaultConcurrency if zero. Messages of different events are always handled independently.
+	// The consumer never holds more than Prefetch unacked messages, of all the events together, so a
+	// concurrency above Prefetch has no effect.
+	Concurrency int `validate:"-"`
+	// EventConcurrency overrides Concurrency for the given events.
+	EventConcurrency map[event.MicroserviceEvent]int `validate:"-"`
+	// CommandConcurrency overrides Concurrency for the given saga commands.
+	CommandConcurrency map[micro.StepCommand]int `validate:"-"`
+	// Unhandled is the policy for the messages without handler, UnhandledParkingLot if empty.
+	Unhandled UnhandledPolicy `validate:"omitempty,oneof=parking_lot ack"`
+	// HandlerGracePeriod is how long, after connecting, a message waits for its handler to be
+	// registered before applying the Unhandled policy, DefaultHandlerGracePeriod if zero. The message
+	// waits without holding up the others, though it keeps its Prefetch slot until it is settled, so
+	// with the default Prefetch of 1 the consumer gets no other message meanwhile. Run ends the grace
+	// period right away.
+	// Once it is over, the subscribed events without handler are logged.
+	HandlerGracePeriod time.Duration `validate:"-"`
+	// RequireHandlers makes Run fail, with an error matching ErrMissingHandlers, when any of the
+	// subscribed events has no handler, see CheckHandlers.
+	RequireHandlers bool `validate:"-"`
+	// PanicStrategy is how the message of a handler that panicked is nacked, PanicRetryFibonacci if empty.
+	PanicStrategy PanicStrategy `validate:"omitempty,oneof=fibonacci delay parking_lot policy"`
+	// Codec encodes the events published with PublishEvent, JSONCodec if nil. The saga messages are
+	// always JSON, as the transactional microservice reads them.
+	Codec Codec `validate:"-"`
+	// Codecs are the additional codecs used to decode the deliveries by their ContentType, JSONCodec
+	// and Codec are always included. They take precedence over the codecs registered with RegisterCodec.
+	Codecs []Codec `validate:"-"`
+	// Compression compresses the payloads published with PublishEvent, CommenceSaga and the saga
+	// replies from CompressionThreshold bytes, they are not compressed if empty.
+	Compression Compression `validate:"omitempty,oneof=gzip zstd"`
+	// CompressionThreshold is the size from which the payloads are compressed, DefaultCompressionThreshold if zero.
+	CompressionThreshold int `validate:"-"`
+	// MaxDecompressedSize is the size over which a compressed delivery is rejected without
+	// reaching the handlers, DefaultMaxDecompressedSize if zero.
+	MaxDecompressedSize int64 `validate:"-"`
+	// RetryPolicy is how the messages of the failed handlers are retried, see ConsumeChannel.Retry.
+	// The zero value retries with the fibonacci backoff up to MAX_NACK_RETRIES times.
+	RetryPolicy RetryPolicy
+	// EventRetryPolicy overrides RetryPolicy for the given events.
+	EventRetryPolicy map[event.MicroserviceEvent]RetryPolicy `validate:"dive"`
+	// CommandRetryPolicy overrides RetryPolicy for the given saga commands.
+	CommandRetryPolicy map[micro.StepCommand]RetryPolicy `validate:"dive"`
+	// Requeue is how the nacked messages wait for their retry, RequeueDelayBuckets if empty.
+	Requeue RequeueStrategy `validate:"omitempty,oneof=delay_buckets delayed_exchange expiration"`
+	// DelayBuckets are the TTLs of the delay queues of RequeueDelayBuckets, DefaultDelayBuckets if empty.
+	// Every consumer queue has a delay queue per bucket.
+	DelayBuckets []time.Duration `validate:"dive,gt=0"`
 }
 
-// RabbitUri is used for send channel connection.
+const (
+	// DefaultPrefetch is the default prefetch count of the consumers.
+	DefaultPrefetch = 1
+	// DefaultConcurrency is the default number of workers of every event and command handler.
+	DefaultConcurrency = 1
+)
+
+// RabbitUri is the uri of the last Transactional created with Config.
+//
+// Deprecated: every Transactional publishes with its own connection, this variable is not used anymore.
 var RabbitUri string
 
+// Config validates the options, connects to RabbitMQ and returns the Transactional.
+// It panics on error, use NewTransactional to handle the error instead.
+//
+// The first Transactional created with Config becomes the default one, used by the deprecated
+// package level publish functions; later calls do not replace it unless it was shut down.
 func Config(opts *Opts) *Transactional {
+	t, err := NewTransactional(opts)
+	if err != nil {
+		panic(err.Error())
+	}
+	RabbitUri = opts.RabbitUri
+
+	storedConfigMu.Lock()
+	defer storedConfigMu.Unlock()
+	switch {
+	case storedConfig == nil || storedConfig.isClosing():
+		storedConfig = t
+	case storedConfig.Microservice != t.Microservice:
+		log.Printf("Config: the default Transactional keeps publishing as %s, use SetDefault to replace it", storedConfig.Microservice)
+	}
+	return t
+}
+
+// NewTransactional validates the options, connects to RabbitMQ and returns the Transactional.
+// The error is an *Error matching ErrInvalidOptions or ErrBrokerUnavailable.
+func NewTransactional(opts *Opts) (*Transactional, error) {
 	err := validate.Struct(opts)
 	if err != nil {
-		panic(fmt.Sprintf("Invalid options: %v", err))
+		return nil, newError(ErrInvalidOptions, "Invalid options", err)
 	}
 
 	if opts.Events == nil {
@@ -77,133 +276,318 @@ func Config(opts *Opts) *Transactional {
 
 	conn, err := amqp.Dial(opts.RabbitUri)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to connect to RabbitMQ: %v", err))
+		return nil, newError(ErrBrokerUnavailable, "Failed to connect to RabbitMQ", err)
 	}
 
-	t := &Transactional{
-		Microservice: opts.Microservice,
-		Events:       opts.Events,
-		conn:         conn,
+	maxReconnectDelay := opts.MaxReconnectDelay
+	if maxReconnectDelay <= 0 {
+		maxReconnectDelay = DefaultMaxReconnectDelay
 	}
-	t.notifyClose()
-	t.isConnected = true
-	RabbitUri = opts.RabbitUri
 
-	// Store configuration for access by publishEvent
-	storedConfig = t
+	shutdownTimeout := opts.ShutdownTimeout
+	if shutdownTimeout <= 0 {
+		shutdownTimeout = DefaultShutdownTimeout
+	}
 
-	return t
+	publishTimeout := opts.PublishTimeout
+	if publishTimeout <= 0 {
+		publishTimeout = DefaultPublishTimeout
+	}
+
+	prefetch := opts.Prefetch
+	if prefetch <= 0 {
+		prefetch = DefaultPrefetch
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency <= 0 {
+		concurrency = DefaultConcurrency
+	}
+
+	unhandled := opts.Unhandled
+	if unhandled == "" {
+		unhandled = UnhandledParkingLot
+	}
+
+	handlerGracePeriod := opts.HandlerGracePeriod
+	if handlerGracePeriod <= 0 {
+		handlerGracePeriod = DefaultHandlerGracePeriod
+	}
+
+	codec := opts.Codec
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+
+	compressionThreshold := opts.CompressionThreshold
+	if compressionThreshold <= 0 {
+		compressionThreshold = DefaultCompressionThreshold
+	}
+
+	maxDecompressedSize := opts.MaxDecompressedSize
+	if maxDecompressedSize <= 0 {
+		maxDecompressedSize = DefaultMaxDecompressedSize
+	}
+
+	requeue := opts.Requeue
+	if requeue == "" {
+		requeue = RequeueDelayBuckets
+	}
+
+	delayBuckets := slices.Clone(opts.DelayBuckets)
+	if len(delayBuckets) == 0 {
+		delayBuckets = slices.Clone(DefaultDelayBuckets)
+	}
+	slices.Sort(delayBuckets)
+	delayBuckets = slices.Compact(delayBuckets)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t := &Transactional{
+		Microservice:         opts.Microservice,
+		Events:               opts.Events,
+		rabbitUri:            opts.RabbitUri,
+		conn:                 conn,
+		isConnected:          true,
+		status:               StatusConnected,
+		done:                 make(chan struct{}),
+		lost:                 make(chan lostResource),
+		onReconnect:          opts.OnReconnect,
+		maxReconnectDelay:    maxReconnectDelay,
+		ctx:                  ctx,
+		cancel:               cancel,
+		shutdownTimeout:      shutdownTimeout,
+		eventsConsumerTag:    fmt.Sprintf("%s_events_%s", opts.Microservice, uuid.NewString()),
+		sagaConsumerTag:      fmt.Sprintf("%s_saga_%s", opts.Microservice, uuid.NewString()),
+		publisher:            newPublisher(opts.RabbitUri, opts.PublisherChannels),
+		publishTimeout:       publishTimeout,
+		prefetch:             prefetch,
+		concurrency:          concurrency,
+		eventConcurrency:     opts.EventConcurrency,
+		commandConcurrency:   opts.CommandConcurrency,
+		unhandled:            unhandled,
+		handlerGracePeriod:   handlerGracePeriod,
+		requireHandlers:      opts.RequireHandlers,
+		panicStrategy:        opts.PanicStrategy,
+		codec:                codec,
+		codecs:               newCodecs(append([]Codec{codec}, opts.Codecs...)...),
+		compression:          opts.Compression,
+		compressionThreshold: compressionThreshold,
+		maxDecompressedSize:  maxDecompressedSize,
+		retryPolicy:          opts.RetryPolicy,
+		eventRetryPolicies:   opts.EventRetryPolicy,
+		commandRetryPolicies: opts.CommandRetryPolicy,
+		requeue:              requeue,
+		delayBuckets:         delayBuckets,
+	}
+	t.notifyClose(conn)
+	go t.supervise()
+
+	return t, nil
 }
 
 // ConnectToSagaCommandEmitter connects to the saga commands exchange and returns an emitter.
+// If the connection is lost, the consumer is resumed on the same emitter once reconnected.
+// It panics on error, use ConnectSagaCommands to handle the error instead.
 func (t *Transactional) ConnectToSagaCommandEmitter() *Emitter[CommandHandler, micro.StepCommand] {
-	sagaChannel, err := t.conn.Channel()
+	e, err := t.ConnectSagaCommands()
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// ConnectSagaCommands is like ConnectToSagaCommandEmitter but returns the error, an *Error
+// matching ErrBrokerUnavailable or ErrTopology.
+func (t *Transactional) ConnectSagaCommands() (*Emitter[CommandHandler, micro.StepCommand], error) {
+	e := newEmitter[CommandHandler, micro.StepCommand](t.prefetch, t.commandConcurrency, t.concurrency, t.handlerGracePeriod)
+	e.recovered = t.commandPanic
+	e.expand = expandCommandHandler
+	e.settle = func(h CommandHandler, err error) {
+		h.Channel.settleWith(err, nil)
+	}
+
+	err := t.consumeSagaCommands(e, nil)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to create sagaChannel: %v", err))
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.sagaEmitter = e
+	t.mu.Unlock()
+	return e, nil
+}
+
+// consumeSagaCommands opens the saga channel in the current connection, unless the channel of a
+// stopped consumer is still open, asserts the saga resources and starts delivering the saga commands
+// into the emitter.
+func (t *Transactional) consumeSagaCommands(e *Emitter[CommandHandler, micro.StepCommand], sagaChannel *amqp.Channel) error {
+	if sagaChannel == nil || sagaChannel.IsClosed() {
+		var err error
+		sagaChannel, err = t.connection().Channel()
+		if err != nil {
+			return newError(ErrBrokerUnavailable, "Failed to create sagaChannel", err)
+		}
+		watchChannel(sagaChannel, "saga commands")
 	}
+	t.mu.Lock()
 	t.sagaChannel = sagaChannel
-	err = t.sagaChannel.Qos(
-		1,     // prefetch count
-		0,     // prefetch size
-		false, // global
+	t.mu.Unlock()
+
+	err := sagaChannel.Qos(
+		t.prefetch, // prefetch count
+		0,          // prefetch size
+		false,      // global
 	)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to set QoS in sagaChannel: %v", err))
+		return newError(ErrBrokerUnavailable, "Failed to set QoS in sagaChannel", err)
 	}
 
 	q := getQueueConsumer(t.Microservice)
-	e := newEmitter[CommandHandler, micro.StepCommand]()
 
 	err = t.createConsumers([]QueueConsumerProps{q})
 	if err != nil {
-		panic(err)
+		return newError(ErrTopology, "Failed to create saga consumers", err)
 	}
 
-	go func() {
-		channelQ, err := t.sagaChannel.Consume(
-			q.QueueName,
-			"",
-			false,
-			false,
-			false,
-			false,
-			nil,
-		)
-		if err != nil {
-			fmt.Println("Error consuming messages:", err)
-		}
+	channelQ, err := sagaChannel.Consume(
+		q.QueueName,
+		t.sagaConsumerTag,
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return newError(ErrTopology, "Failed to consume messages", err)
+	}
 
+	stopped := t.notifyStopped(sagaChannel, "saga commands", func(ch *amqp.Channel) error {
+		return t.consumeSagaCommands(e, ch)
+	})
+	go func() {
+		// the range ends when the channel or the connection is closed, or the consumer is cancelled
 		for msg := range channelQ {
-			t.sagaCommandCallback(&msg, e, q.QueueName)
+			t.sagaCommandCallback(sagaChannel, &msg, e, q.QueueName)
 		}
+		stopped()
 	}()
 
-	return e
+	return nil
 }
 
 // ConnectToEvents connects to the events exchange and returns an emitter.
+// If the connection is lost, the consumer is resumed on the same emitter once reconnected.
+// It panics on error, use ConnectEvents to handle the error instead.
 func (t *Transactional) ConnectToEvents() *Emitter[EventHandler, event.MicroserviceEvent] {
-	eventsChannel, err := t.conn.Channel()
+	e, err := t.ConnectEvents()
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// ConnectEvents is like ConnectToEvents but returns the error, an *Error matching
+// ErrBrokerUnavailable or ErrTopology.
+func (t *Transactional) ConnectEvents() (*Emitter[EventHandler, event.MicroserviceEvent], error) {
+	e := newEmitter[EventHandler, event.MicroserviceEvent](t.prefetch, t.eventConcurrency, t.concurrency, t.handlerGracePeriod)
+	e.recovered = t.eventPanic
+	e.expand = expandEventHandler
+	e.settle = func(h EventHandler, err error) {
+		h.Channel.settleWith(err)
+	}
+
+	err := t.consumeEvents(e, nil)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to create sagaChannel: %v", err))
+		return nil, err
+	}
+
+	t.mu.Lock()
+	t.eventsEmitter = e
+	t.mu.Unlock()
+	t.warnMissingHandlers()
+	return e, nil
+}
+
+// consumeEvents opens the events channel in the current connection, unless the channel of a stopped
+// consumer is still open, asserts the events and audit resources and starts delivering the events
+// into the emitter.
+func (t *Transactional) consumeEvents(e *Emitter[EventHandler, event.MicroserviceEvent], eventsChannel *amqp.Channel) error {
+	if eventsChannel == nil || eventsChannel.IsClosed() {
+		var err error
+		eventsChannel, err = t.connection().Channel()
+		if err != nil {
+			return newError(ErrBrokerUnavailable, "Failed to create eventsChannel", err)
+		}
+		watchChannel(eventsChannel, "events")
 	}
+	t.mu.Lock()
 	t.eventsChannel = eventsChannel
-	err = t.eventsChannel.Qos(
-		1,     // prefetch count
-		0,     // prefetch size
-		false, // global
+	t.mu.Unlock()
+
+	err := eventsChannel.Qos(
+		t.prefetch, // prefetch count
+		0,          // prefetch size
+		false,      // global
 	)
 	if err != nil {
-		panic(fmt.Sprintf("Failed to set QoS in eventsChannel: %v", err))
+		return newError(ErrBrokerUnavailable, "Failed to set QoS in eventsChannel", err)
 	}
 
-	queueName := fmt.Sprintf("%s_match_commands", t.Microservice)
-	e := newEmitter[EventHandler, event.MicroserviceEvent]()
+	queueName := getEventsQueueName(t.Microservice)
 
 	err = t.createHeaderConsumer(queueName, t.Events)
 	if err != nil {
-		panic(err)
+		return newError(ErrTopology, "Failed to create events consumer", err)
 	}
 
 	// Create audit logging resources - this feature is related only to "events"
 	err = t.createAuditLoggingResources()
 	if err != nil {
-		panic(fmt.Sprintf("Failed to create audit logging resources: %v", err))
+		return newError(ErrTopology, "Failed to create audit logging resources", err)
 	}
 
-	go func() {
-		channelQ, err := t.eventsChannel.Consume(
-			queueName,
-			"",
-			false,
-			false,
-			false,
-			false,
-			nil,
-		)
-		if err != nil {
-			fmt.Println("Error consuming messages:", err)
-		}
+	channelQ, err := eventsChannel.Consume(
+		queueName,
+		t.eventsConsumerTag,
+		false,
+		false,
+		false,
+		false,
+		nil,
+	)
+	if err != nil {
+		return newError(ErrTopology, "Failed to consume messages", err)
+	}
 
+	stopped := t.notifyStopped(eventsChannel, "events", func(ch *amqp.Channel) error {
+		return t.consumeEvents(e, ch)
+	})
+	go func() {
+		// the range ends when the channel or the connection is closed, or the consumer is cancelled
 		for msg := range channelQ {
-			t.eventCallback(&msg, e, queueName)
+			t.eventCallback(eventsChannel, &msg, e, queueName)
 		}
+		stopped()
 	}()
 
-	return e
+	return nil
 }
 
 // HealthCheck checks if the rabbitmq connection is alive and the queue exists.
 // The queue to check is the microservice related to the saga commands or events.
 func (t *Transactional) HealthCheck() error {
-	if !t.isConnected {
+	t.mu.Lock()
+	isConnected, conn, healthCheckQueue := t.isConnected, t.conn, t.healthCheckQueue
+	t.mu.Unlock()
+	if !isConnected {
 		return fmt.Errorf("rabbitmq is not connected")
 	}
 
-	if t.healthCheckQueue == "" {
+	if healthCheckQueue == "" {
 		return fmt.Errorf("health check queue is not set")
 	}
-	channel, err := t.conn.Channel()
+	channel, err := conn.Channel()
 
 	defer func(channel *amqp.Channel) {
 		if channel != nil {
@@ -219,7 +603,7 @@ func (t *Transactional) HealthCheck() error {
 
 	}
 
-	_, err = channel.QueueDeclarePassive(t.healthCheckQueue, true, false, false, false, nil)
+	_, err = channel.QueueDeclarePassive(healthCheckQueue, true, false, false, false, nil)
 	if err != nil {
 		fmt.Println("queue error")
 		return err
@@ -228,27 +612,38 @@ func (t *Transactional) HealthCheck() error {
 	return nil
 }
 
-// StopRabbitMQ closes the rabbitmq connection and channels.
+// StopRabbitMQ shuts down the Transactional gracefully, waiting up to Opts.ShutdownTimeout for
+// the in-flight messages, see Shutdown.
 func (t *Transactional) StopRabbitMQ() error {
-	var err error
+	ctx, cancel := context.WithTimeout(context.Background(), t.shutdownTimeout)
+	defer cancel()
+	return t.Shutdown(ctx)
+}
+
+// close closes the rabbitmq channels and connection.
+func (t *Transactional) close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	var errs []error
 	if t.eventsChannel != nil {
-		err = t.eventsChannel.Close()
+		errs = append(errs, ignoreClosed(t.eventsChannel.Close()))
 	}
 	if t.sagaChannel != nil {
-		err = t.sagaChannel.Close()
+		errs = append(errs, ignoreClosed(t.sagaChannel.Close()))
 	}
 	if t.conn != nil {
-		err = t.conn.Close()
+		errs = append(errs, ignoreClosed(t.conn.Close()))
 	}
-	return err
+	t.isConnected = false
+	t.status = StatusClosed
+	return errors.Join(errs...)
 }
 
-func (t *Transactional) notifyClose() {
-	closeReceiver := make(chan *amqp.Error)
-	t.conn.NotifyClose(closeReceiver)
-
-	go func() {
-		<-closeReceiver
-		t.isConnected = false
-	}()
+// ignoreClosed returns nil for amqp.ErrClosed, closing what the broker or a reconnection already
+// closed is not an error.
+func ignoreClosed(err error) error {
+	if errors.Is(err, amqp.ErrClosed) {
+		return nil
+	}
+	return err
 }
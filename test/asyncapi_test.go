@@ -0,0 +1,76 @@
+package test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/legendaryum-metaverse/saga/asyncapi"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+// resolve follows a local reference of the document.
+func resolve(t *testing.T, doc map[string]any, ref string) any {
+	var node any = doc
+	for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		obj, ok := node.(map[string]any)
+		require.True(t, ok, ref)
+		node, ok = obj[key]
+		require.True(t, ok, ref)
+	}
+	return node
+}
+
+func checkRefs(t *testing.T, doc map[string]any, node any) {
+	switch n := node.(type) {
+	case map[string]any:
+		for k, v := range n {
+			if k == "$ref" {
+				resolve(t, doc, v.(string))
+			} else {
+				checkRefs(t, doc, v)
+			}
+		}
+	case []any:
+		for _, v := range n {
+			checkRefs(t, doc, v)
+		}
+	}
+}
+
+func TestAsyncAPI(t *testing.T) {
+	doc := asyncapi.Generate(asyncapi.Options{Title: "saga", Version: "1.0.0", Host: "localhost:5672"})
+
+	assert.Len(t, doc.Channels, len(event.MicroserviceEventValues())+3)
+	channel := doc.Channels[string(event.SocialNewUserEvent)]
+	require.NotNil(t, channel)
+	assert.Equal(t, "headers", channel.Bindings.AMQP.Exchange.Type)
+	assert.Equal(t, "send", doc.Operations["publish.social.new_user"].Action)
+	assert.Equal(t, "direct", doc.Channels[string(event.AuditPublishedEvent)].Bindings.AMQP.Exchange.Type)
+
+	msg := doc.Components.Messages[string(event.SocialNewUserEvent)]
+	assert.Contains(t, msg.Headers.Required, "SOCIAL.NEW_USER")
+	assert.Equal(t, "#/components/schemas/SocialNewUserPayload", msg.Payload.Ref)
+
+	user := doc.Components.Schemas["SocialUser"]
+	assert.ElementsMatch(t, []string{"_id", "username", "email"}, user.Required)
+	assert.Equal(t, "email", user.Properties["email"].Format)
+	assert.Equal(t, "date-time", user.Properties["createdAt"].Format)
+	assert.Equal(t, []any{"MALE", "FEMALE", "UNDEFINED"}, user.Properties["gender"].Enum)
+
+	data, err := doc.JSON()
+	require.NoError(t, err)
+	var fromJSON map[string]any
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	checkRefs(t, fromJSON, fromJSON)
+
+	data, err = doc.YAML()
+	require.NoError(t, err)
+	var fromYAML map[string]any
+	require.NoError(t, yaml.Unmarshal(data, &fromYAML))
+	assert.Equal(t, "3.0.0", fromYAML["asyncapi"])
+	assert.Len(t, fromYAML["channels"], len(doc.Channels))
+}
@@ -0,0 +1,48 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/legendaryum-metaverse/saga/catalog"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/legendaryum-metaverse/saga/micro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	c, err := catalog.Load("../catalog/catalog.yaml")
+	require.NoError(t, err)
+
+	for _, ev := range c.Events {
+		payload, ok := event.Lookup(event.MicroserviceEvent(ev.Name))
+		require.True(t, ok, ev.Name)
+		assert.Equal(t, ev.Payload.Name, payload.Name())
+	}
+	for _, m := range c.Microservices {
+		assert.True(t, micro.AvailableMicroservices(m.Name).IsValid(), m.Name)
+		for _, cmd := range m.Commands {
+			_, ok := micro.LookupCommand(cmd.Name)
+			assert.True(t, ok, cmd.Name)
+		}
+	}
+
+	_, err = catalog.Parse([]byte("events:\n  - const: AEvent\n    name: a.b\n    payload: {name: APayload}\n    unknown: 1\n"))
+	require.Error(t, err)
+
+	_, err = catalog.Parse([]byte(`
+events:
+  - const: AEvent
+    name: a.b
+    payload:
+      name: APayload
+      fields:
+        - {name: B, json: b, type: Missing}
+  - const: AEvent
+    name: a.c
+    payload: {name: CPayload}
+`))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `undefined type "Missing"`)
+	assert.Contains(t, err.Error(), `duplicated event const "AEvent"`)
+}
@@ -0,0 +1,104 @@
+package test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+func TestCodecs(t *testing.T) {
+	payload := event.BillingPaymentSucceededPayload{
+		PaymentID:  "pay_1",
+		UserID:     "1234",
+		Amount:     9007199254740993,
+		Currency:   "usd",
+		Metadata:   map[string]string{"plan": "pro"},
+		OccurredAt: "2025-01-01T00:00:00Z",
+	}
+
+	t.Run("msgpack uses the json field names", func(t *testing.T) {
+		codec := saga.MsgpackCodec{}
+		body, err := codec.Marshal(payload)
+		require.NoError(t, err)
+
+		var decoded event.BillingPaymentSucceededPayload
+		require.NoError(t, codec.Unmarshal(body, &decoded))
+		assert.Equal(t, payload, decoded)
+
+		var untyped map[string]interface{}
+		require.NoError(t, codec.Unmarshal(body, &untyped))
+		assert.Equal(t, "pay_1", untyped["paymentId"])
+	})
+
+	t.Run("protobuf", func(t *testing.T) {
+		codec := saga.ProtobufCodec{}
+		body, err := codec.Marshal(wrapperspb.String("mint"))
+		require.NoError(t, err)
+
+		var decoded *wrapperspb.StringValue
+		require.NoError(t, codec.Unmarshal(body, &decoded))
+		assert.Equal(t, "mint", decoded.GetValue())
+
+		_, err = codec.Marshal(payload)
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodeByContentType(t *testing.T) {
+	// the Transactional encodes with JSON, it decodes the other content types with the registered codecs
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+	})
+	received := make(chan string, 1)
+	saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		received <- p.Image
+		return nil
+	})
+
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	queue := string(transactional.Microservice) + "_match_commands"
+	headers := amqp.Table{saga.EventHeaderKey(event.TestImageEvent): string(event.TestImageEvent)}
+	publish := func(msg amqp.Publishing) {
+		require.NoError(t, ch.PublishWithContext(context.Background(), "", queue, false, false, msg))
+	}
+
+	body, err := saga.MsgpackCodec{}.Marshal(event.TestImagePayload{Image: "msgpack"})
+	require.NoError(t, err)
+	publish(amqp.Publishing{Headers: headers, ContentType: saga.ContentTypeMsgpack, Body: body})
+	select {
+	case image := <-received:
+		assert.Equal(t, "msgpack", image)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the msgpack event was not decoded")
+	}
+
+	// the deliveries that cannot be decoded are parked rather than dropped
+	publish(amqp.Publishing{Headers: headers, ContentType: "application/unknown", Body: []byte("image")})
+	publish(amqp.Publishing{Headers: headers, ContentType: saga.ContentTypeJSON, ContentEncoding: "gzip", Body: []byte("not gzip")})
+	publish(amqp.Publishing{Headers: headers, ContentType: saga.ContentTypeJSON, Body: []byte(`["image"]`)})
+	publish(amqp.Publishing{ContentType: saga.ContentTypeJSON, Body: []byte(`{"image": "no event"}`)})
+
+	var parked []saga.ParkedMessage
+	require.Eventually(t, func() bool {
+		parked, err = transactional.ParkedMessages(context.Background(), saga.ParkedFilter{})
+		return err == nil && len(parked) == 4
+	}, 10*time.Second, 50*time.Millisecond)
+	for _, m := range parked {
+		assert.True(t, strings.HasPrefix(m.Reason, "decode: "), m.Reason)
+		assert.Equal(t, queue, m.Queue)
+	}
+	assert.Empty(t, received, "an undecodable event reached the handler")
+}
@@ -0,0 +1,174 @@
+package test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/legendaryum-metaverse/saga/micro"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedEvents(t *testing.T) {
+	for _, compression := range []saga.Compression{saga.CompressionGzip, saga.CompressionZstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			transactional, emitter := connectTestEvents(t, saga.Opts{
+				Events:               []event.MicroserviceEvent{event.TestImageEvent},
+				Compression:          compression,
+				CompressionThreshold: 1024,
+			})
+			received := make(chan event.TestImagePayload, 1)
+			saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+				received <- p
+				return nil
+			})
+
+			image := strings.Repeat("a", 64<<10)
+			require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: image}))
+			select {
+			case p := <-received:
+				require.Equal(t, image, p.Image)
+			case <-time.After(10 * time.Second):
+				t.Fatal("the compressed event was not received")
+			}
+		})
+	}
+}
+
+func TestOversizedDecompression(t *testing.T) {
+	for _, compression := range []saga.Compression{saga.CompressionGzip, saga.CompressionZstd} {
+		t.Run(string(compression), func(t *testing.T) {
+			transactional, emitter := connectTestEvents(t, saga.Opts{
+				Events:               []event.MicroserviceEvent{event.TestImageEvent},
+				Compression:          compression,
+				CompressionThreshold: 1024,
+				MaxDecompressedSize:  16 << 10,
+			})
+			received := make(chan event.TestImagePayload, 1)
+			saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+				received <- p
+				return nil
+			})
+
+			// a few hundred bytes compressed, 64 KiB decompressed
+			require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: strings.Repeat("a", 64<<10)}))
+
+			var (
+				parked []saga.ParkedMessage
+				err    error
+			)
+			require.Eventually(t, func() bool {
+				parked, err = transactional.ParkedMessages(context.Background(), saga.ParkedFilter{})
+				return err == nil && len(parked) == 1
+			}, 10*time.Second, 50*time.Millisecond)
+			assert.True(t, strings.HasPrefix(parked[0].Reason, "decode: "), parked[0].Reason)
+			assert.Empty(t, received, "the oversized event reached the handler")
+		})
+	}
+}
+
+// gunzip decompresses a gzip body.
+func gunzip(t *testing.T, body []byte) []byte {
+	t.Helper()
+	r, err := gzip.NewReader(bytes.NewReader(body))
+	require.NoError(t, err)
+	defer r.Close()
+	decompressed, err := io.ReadAll(r)
+	require.NoError(t, err)
+	return decompressed
+}
+
+func TestCompressedSagaStep(t *testing.T) {
+	transactional := newTestTransactional(t, saga.Opts{
+		Compression:          saga.CompressionGzip,
+		CompressionThreshold: 1,
+	})
+	emitter, err := transactional.ConnectSagaCommands()
+	require.NoError(t, err)
+	transfer := micro.RegisterCommand[micro.TransferMissionRewardToWinnerPayload, transferResult]("test:compressed_transfer")
+	saga.OnCommand(emitter, transfer, func(_ context.Context, p micro.TransferMissionRewardToWinnerPayload, _ *saga.MicroserviceConsumeChannel) (transferResult, error) {
+		return transferResult{TxID: "tx-" + p.UserID}, nil
+	})
+
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	_, err = ch.QueueDeclare(string(saga.ReplyToSagaQ), true, false, false, false, nil)
+	require.NoError(t, err)
+	replies, err := ch.Consume(string(saga.ReplyToSagaQ), "", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	// the transactional microservice may compress the steps it sends
+	body, err := json.Marshal(saga.SagaStep{
+		Microservice:    transactional.Microservice,
+		Command:         string(transfer.Name),
+		Status:          saga.Sent,
+		SagaID:          1,
+		Payload:         map[string]interface{}{},
+		PreviousPayload: map[string]interface{}{"walletAddress": "wallet", "userId": "user", "reward": "10"},
+		IsCurrentStep:   true,
+	})
+	require.NoError(t, err)
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	_, err = gz.Write(body)
+	require.NoError(t, err)
+	require.NoError(t, gz.Close())
+	err = ch.PublishWithContext(context.Background(), "", string(transactional.Microservice)+"_saga_commands", false, false, amqp.Publishing{
+		ContentType:     saga.ContentTypeJSON,
+		ContentEncoding: string(saga.CompressionGzip),
+		Body:            compressed.Bytes(),
+	})
+	require.NoError(t, err)
+
+	timeout := time.After(10 * time.Second)
+	for replied := false; !replied; {
+		select {
+		case msg := <-replies:
+			if msg.ContentEncoding != string(saga.CompressionGzip) {
+				continue
+			}
+			var step saga.SagaStep
+			require.NoError(t, json.Unmarshal(gunzip(t, msg.Body), &step))
+			if step.Microservice != transactional.Microservice {
+				continue
+			}
+			replied = true
+			assert.Equal(t, saga.Success, step.Status)
+			assert.Equal(t, map[string]interface{}{"txId": "tx-user"}, step.Payload)
+		case <-timeout:
+			t.Fatal("the compressed saga step was not replied")
+		}
+	}
+
+	// CommenceSaga compresses as well
+	sagas := commencedSagas(t)
+	require.NoError(t, transactional.CommenceSaga(saga.TransferCryptoRewardToMissionWinnerPayload{
+		WalletAddress: "wallet",
+		UserID:        "user",
+		Reward:        "10",
+	}))
+	select {
+	case msg := <-sagas:
+		assert.Equal(t, string(saga.CompressionGzip), msg.ContentEncoding)
+		var commenced struct {
+			Title saga.SagaTitle `json:"title"`
+		}
+		require.NoError(t, json.Unmarshal(gunzip(t, msg.Body), &commenced))
+		assert.Equal(t, saga.TransferCryptoRewardToMissionWinner, commenced.Title)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the saga was not commenced")
+	}
+}
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConcurrentHandlers(t *testing.T) {
+	const concurrency = 4
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events:      []event.MicroserviceEvent{event.TestImageEvent},
+		Prefetch:    concurrency,
+		Concurrency: concurrency,
+	})
+	var running atomic.Int32
+	release := make(chan struct{})
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		running.Add(1)
+		defer running.Add(-1)
+		<-release
+		return nil
+	})
+
+	for range concurrency {
+		require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "concurrent"}))
+	}
+	// every handler blocks until released, they all run at the same time
+	assert.Eventually(t, func() bool {
+		return running.Load() == concurrency
+	}, 10*time.Second, 10*time.Millisecond)
+	close(release)
+	assert.Eventually(t, func() bool {
+		return running.Load() == 0
+	}, 10*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,128 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/legendaryum-metaverse/saga/micro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// keepDefault restores the default Transactional of the other tests once the test ends.
+func keepDefault(t *testing.T) {
+	previous := saga.GetStoredConfig()
+	t.Cleanup(func() {
+		saga.SetDefault(previous)
+	})
+}
+
+// publishers receives the AppID of the test.image events, the publisher microservice of each one.
+func publishers(t *testing.T) <-chan string {
+	_, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+	})
+	appIDs := make(chan string, 16)
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, ch *saga.EventsConsumeChannel) error {
+		appIDs <- ch.AppID()
+		return nil
+	})
+	return appIDs
+}
+
+func receive[T any](t *testing.T, ch <-chan T) T {
+	t.Helper()
+	select {
+	case v := <-ch:
+		return v
+	case <-time.After(10 * time.Second):
+		t.Fatal("nothing received")
+	}
+	var zero T
+	return zero
+}
+
+func TestDeprecatedShims(t *testing.T) {
+	keepDefault(t)
+	appIDs := publishers(t)
+	audits := auditEvents(t, event.AuditProcessedEvent)
+	sagas := commencedSagas(t)
+
+	saga.SetDefault(nil)
+	assert.Error(t, saga.PublishEvent(&event.TestImagePayload{Image: "image"}))
+	assert.Error(t, saga.PublishAuditEvent(&event.AuditProcessedPayload{}))
+	assert.Error(t, saga.CommenceSaga(saga.TransferCryptoRewardToMissionWinnerPayload{}))
+
+	transactional := newTestTransactional(t, saga.Opts{Microservice: micro.Auth})
+	saga.SetDefault(transactional)
+	assert.Same(t, transactional, saga.GetStoredConfig())
+
+	require.NoError(t, saga.PublishEvent(&event.TestImagePayload{Image: "image"}))
+	assert.Equal(t, string(micro.Auth), receive(t, appIDs))
+
+	require.NoError(t, saga.PublishAuditEvent(&event.AuditProcessedPayload{
+		PublisherMicroservice: string(micro.Social),
+		ProcessorMicroservice: string(micro.Auth),
+		ProcessedEvent:        string(event.TestImageEvent),
+		ProcessedAt:           uint64(time.Now().UnixMilli()),
+		EventID:               "event-id",
+	}))
+	var processed event.AuditProcessedPayload
+	require.NoError(t, json.Unmarshal(receive(t, audits).Body, &processed))
+	assert.Equal(t, "event-id", processed.EventID)
+
+	require.NoError(t, saga.CommenceSaga(saga.TransferCryptoRewardToMissionWinnerPayload{
+		WalletAddress: "wallet",
+		UserID:        "1234",
+		Reward:        "10",
+	}))
+	var commenced struct {
+		Title saga.SagaTitle `json:"title"`
+	}
+	require.NoError(t, json.Unmarshal(receive(t, sagas).Body, &commenced))
+	assert.Equal(t, saga.TransferCryptoRewardToMissionWinner, commenced.Title)
+}
+
+func TestConfigKeepsFirstDefault(t *testing.T) {
+	keepDefault(t)
+	saga.SetDefault(nil)
+
+	config := func(microservice micro.AvailableMicroservices) *saga.Transactional {
+		transactional := saga.Config(&saga.Opts{RabbitUri: rabbitURI, Microservice: microservice})
+		t.Cleanup(func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			assert.NoError(t, transactional.Shutdown(ctx))
+		})
+		return transactional
+	}
+	first := config(micro.Auth)
+	config(micro.Social)
+	assert.Same(t, first, saga.GetStoredConfig())
+
+	// once the default is shut down, the next Config replaces it
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, first.Shutdown(ctx))
+	third := config(micro.Showcase)
+	assert.Same(t, third, saga.GetStoredConfig())
+}
+
+func TestTransactionalsPublishAsTheirMicroservice(t *testing.T) {
+	keepDefault(t)
+	appIDs := publishers(t)
+	auth := newTestTransactional(t, saga.Opts{Microservice: micro.Auth})
+	social := newTestTransactional(t, saga.Opts{Microservice: micro.Social})
+	saga.SetDefault(auth)
+
+	require.NoError(t, social.PublishEvent(&event.TestImagePayload{Image: "social"}))
+	assert.Equal(t, string(micro.Social), receive(t, appIDs))
+	require.NoError(t, auth.PublishEvent(&event.TestImagePayload{Image: "auth"}))
+	assert.Equal(t, string(micro.Auth), receive(t, appIDs))
+	require.NoError(t, social.PublishEvent(&event.TestImagePayload{Image: "social"}))
+	assert.Equal(t, string(micro.Social), receive(t, appIDs))
+}
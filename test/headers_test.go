@@ -0,0 +1,57 @@
+package test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeaderInt(t *testing.T) {
+	headers := amqp.Table{
+		"int8":    int8(3),
+		"int16":   int16(3),
+		"int32":   int32(3),
+		"int64":   int64(3),
+		"uint8":   uint8(3),
+		"uint16":  uint16(3),
+		"uint32":  uint32(3),
+		"float32": float32(3),
+		"float64": float64(3),
+		"decimal": amqp.Decimal{Scale: 2, Value: 300},
+		"string":  " 3 ",
+		"bytes":   []byte("3"),
+	}
+	for key := range headers {
+		n, ok := saga.HeaderInt(headers, key)
+		assert.True(t, ok, key)
+		assert.EqualValues(t, 3, n, key)
+	}
+
+	for key, v := range map[string]any{
+		"fraction":     3.5,
+		"text":         "three",
+		"bool":         true,
+		"overflow":     uint64(1 << 63),
+		"float range":  1e20,
+		"decimal frac": amqp.Decimal{Scale: 1, Value: 35},
+	} {
+		_, ok := saga.HeaderInt(amqp.Table{key: v}, key)
+		assert.False(t, ok, key)
+	}
+	_, ok := saga.HeaderInt(nil, "x-retry-count")
+	assert.False(t, ok)
+}
+
+func TestHeaderTime(t *testing.T) {
+	at := time.UnixMilli(1760000000123)
+	for _, v := range []any{at.UnixMilli(), float64(at.UnixMilli()), "1760000000123", at, at.Format(time.RFC3339Nano)} {
+		got, ok := saga.HeaderTime(amqp.Table{saga.FirstFailureAtHeader: v}, saga.FirstFailureAtHeader)
+		assert.True(t, ok, "%T", v)
+		assert.True(t, at.Equal(got), "%T", v)
+	}
+	_, ok := saga.HeaderTime(amqp.Table{saga.FirstFailureAtHeader: "yesterday"}, saga.FirstFailureAtHeader)
+	assert.False(t, ok)
+}
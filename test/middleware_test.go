@@ -0,0 +1,121 @@
+package test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMiddlewares(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent, event.TestMintEvent},
+	})
+	var (
+		mu    sync.Mutex
+		calls []string
+	)
+	record := func(call string) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls = append(calls, call)
+	}
+	done := make(chan struct{}, 2)
+
+	emitter.Use(func(next saga.Handler[saga.EventHandler]) saga.Handler[saga.EventHandler] {
+		return func(h saga.EventHandler) {
+			record("first")
+			next(h)
+		}
+	}, func(next saga.Handler[saga.EventHandler]) saga.Handler[saga.EventHandler] {
+		return func(h saga.EventHandler) {
+			record("second")
+			if h.Channel.EventType() == event.TestMintEvent {
+				// short-circuits the handler
+				h.Channel.AckMessage()
+				done <- struct{}{}
+				return
+			}
+			next(h)
+		}
+	})
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		record("image")
+		done <- struct{}{}
+		return nil
+	})
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestMintPayload, _ *saga.EventsConsumeChannel) error {
+		record("mint")
+		return nil
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "image"}))
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the image was not handled")
+	}
+	require.NoError(t, transactional.PublishEvent(&event.TestMintPayload{Mint: "mint"}))
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("the mint was not acked by the middleware")
+	}
+
+	// the acked mint is not redelivered to the middlewares
+	time.Sleep(200 * time.Millisecond)
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"first", "second", "image", "first", "second"}, calls)
+}
+
+func TestMiddlewaresWrapOnce(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+	})
+	var wraps, lateWraps, lateCalls atomic.Int32
+	emitter.Use(func(next saga.Handler[saga.EventHandler]) saga.Handler[saga.EventHandler] {
+		wraps.Add(1)
+		return next
+	})
+	handled := make(chan struct{}, 4)
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		handled <- struct{}{}
+		return nil
+	})
+	handle := func() {
+		t.Helper()
+		require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "image"}))
+		select {
+		case <-handled:
+		case <-time.After(10 * time.Second):
+			t.Fatal("the image was not handled")
+		}
+	}
+
+	for range 3 {
+		handle()
+	}
+	assert.Equal(t, int32(1), wraps.Load(), "the handler is wrapped when it is registered only")
+
+	// Use wraps the registered handler again, the new middleware runs from the next message
+	emitter.Use(func(next saga.Handler[saga.EventHandler]) saga.Handler[saga.EventHandler] {
+		lateWraps.Add(1)
+		return func(h saga.EventHandler) {
+			lateCalls.Add(1)
+			next(h)
+		}
+	})
+	assert.Equal(t, int32(2), wraps.Load())
+	assert.Equal(t, int32(1), lateWraps.Load())
+	handle()
+	handle()
+	assert.Equal(t, int32(2), wraps.Load())
+	assert.Equal(t, int32(2), lateCalls.Load())
+}
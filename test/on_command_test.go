@@ -0,0 +1,98 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/micro"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type transferResult struct {
+	TxID string `json:"txId"`
+}
+
+func TestOnCommand(t *testing.T) {
+	transactional := newTestTransactional(t, saga.Opts{})
+	emitter, err := transactional.ConnectSagaCommands()
+	require.NoError(t, err)
+	transfer := micro.RegisterCommand[micro.TransferMissionRewardToWinnerPayload, transferResult]("test:transfer")
+	received := make(chan micro.TransferMissionRewardToWinnerPayload, 2)
+	saga.OnCommand(emitter, transfer, func(_ context.Context, p micro.TransferMissionRewardToWinnerPayload, _ *saga.MicroserviceConsumeChannel) (transferResult, error) {
+		received <- p
+		return transferResult{TxID: "tx-" + p.UserID}, nil
+	})
+
+	// the replies of the steps are read from the queue of the transactional microservice
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	_, err = ch.QueueDeclare(string(saga.ReplyToSagaQ), true, false, false, false, nil)
+	require.NoError(t, err)
+	replies, err := ch.Consume(string(saga.ReplyToSagaQ), "", true, false, false, false, nil)
+	require.NoError(t, err)
+
+	publishStep := func(sagaID int, previousPayload map[string]interface{}) {
+		body, err := json.Marshal(saga.SagaStep{
+			Microservice:    transactional.Microservice,
+			Command:         string(transfer.Name),
+			Status:          saga.Sent,
+			SagaID:          sagaID,
+			Payload:         map[string]interface{}{},
+			PreviousPayload: previousPayload,
+			IsCurrentStep:   true,
+		})
+		require.NoError(t, err)
+		err = ch.PublishWithContext(context.Background(), "", string(transactional.Microservice)+"_saga_commands", false, false, amqp.Publishing{
+			ContentType: saga.ContentTypeJSON,
+			Body:        body,
+		})
+		require.NoError(t, err)
+	}
+	// the second step misses the required fields of the payload
+	publishStep(1, map[string]interface{}{"walletAddress": "wallet", "userId": "user", "reward": "10", "__meta": "kept"})
+	publishStep(2, map[string]interface{}{"userId": "invalid"})
+
+	select {
+	case p := <-received:
+		assert.Equal(t, micro.TransferMissionRewardToWinnerPayload{WalletAddress: "wallet", UserID: "user", Reward: "10"}, p)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the saga command was not received")
+	}
+
+	timeout := time.After(10 * time.Second)
+	for replied := false; !replied; {
+		select {
+		case msg := <-replies:
+			var step saga.SagaStep
+			require.NoError(t, json.Unmarshal(msg.Body, &step))
+			if step.Microservice != transactional.Microservice {
+				continue
+			}
+			replied = true
+			assert.Equal(t, 1, step.SagaID)
+			assert.Equal(t, saga.Success, step.Status)
+			// the result and the metadata of the previous payload are passed to the next step
+			assert.Equal(t, map[string]interface{}{"txId": "tx-user", "__meta": "kept"}, step.Payload)
+		case <-timeout:
+			t.Fatal("the saga step was not replied")
+		}
+	}
+
+	var parked []saga.ParkedMessage
+	require.Eventually(t, func() bool {
+		parked, err = transactional.ParkedMessages(context.Background(), saga.ParkedFilter{Handler: string(transfer.Name)})
+		return err == nil && len(parked) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Contains(t, parked[0].Reason, "rejected: ")
+	assert.Equal(t, string(transactional.Microservice)+"_saga_commands", parked[0].Queue)
+	assert.Empty(t, received, "the invalid payload reached the handler")
+}
@@ -0,0 +1,77 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnEvent(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+	})
+	deadLetters := auditEvents(t, event.AuditDeadLetterEvent)
+	received := make(chan event.TestImagePayload, 3)
+	saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		received <- p
+		return nil
+	})
+
+	// the invalid payload does not pass the validation, the undecodable one is published by hand
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{}, saga.SkipValidation()))
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	err = ch.PublishWithContext(context.Background(), string(saga.MatchingExchange), "", false, false, amqp.Publishing{
+		Headers:     amqp.Table{"all-micro": "yes", saga.EventHeaderKey(event.TestImageEvent): string(event.TestImageEvent)},
+		ContentType: saga.ContentTypeJSON,
+		Body:        []byte(`{"image": 5}`),
+		AppId:       string(transactional.Microservice),
+	})
+	require.NoError(t, err)
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "valid"}))
+
+	select {
+	case p := <-received:
+		assert.Equal(t, "valid", p.Image)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the valid event was not received")
+	}
+
+	var parked []saga.ParkedMessage
+	require.Eventually(t, func() bool {
+		parked, err = transactional.ParkedMessages(context.Background(), saga.ParkedFilter{Handler: string(event.TestImageEvent)})
+		return err == nil && len(parked) == 2
+	}, 10*time.Second, 50*time.Millisecond)
+	for _, m := range parked {
+		assert.Contains(t, m.Reason, "rejected: ")
+	}
+	assert.Empty(t, received, "a rejected payload reached the handler")
+
+	queue := string(transactional.Microservice) + "_match_commands"
+	rejected := 0
+	timeout := time.After(10 * time.Second)
+	for rejected < 2 {
+		select {
+		case msg := <-deadLetters:
+			var deadLetter event.AuditDeadLetterPayload
+			require.NoError(t, json.Unmarshal(msg.Body, &deadLetter))
+			if deadLetter.QueueName == queue {
+				assert.Contains(t, deadLetter.RejectionReason, "rejected: ")
+				rejected++
+			}
+		case <-timeout:
+			t.Fatal("the audit.dead_letter events of the rejected payloads were not published")
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerOutcomes(t *testing.T) {
+	cause := errors.New("insufficient funds")
+
+	var retry *saga.RetryError
+	require.ErrorAs(t, saga.Retry(cause, 5*time.Second), &retry)
+	assert.Equal(t, 5*time.Second, retry.Delay)
+	assert.False(t, retry.Fibonacci)
+
+	require.ErrorAs(t, saga.RetryFibonacci(cause), &retry)
+	assert.True(t, retry.Fibonacci)
+
+	var rejected *saga.RejectError
+	require.ErrorAs(t, saga.Reject(cause), &rejected)
+	assert.ErrorIs(t, rejected, cause)
+}
+
+func TestRetryPolicy(t *testing.T) {
+	var zero saga.RetryPolicy
+	assert.Equal(t, []time.Duration{time.Second, time.Second, 2 * time.Second, 3 * time.Second, 5 * time.Second},
+		[]time.Duration{zero.DelayFor(1), zero.DelayFor(2), zero.DelayFor(3), zero.DelayFor(4), zero.DelayFor(5)})
+
+	fixed := saga.RetryPolicy{Backoff: saga.BackoffFixed}
+	assert.Equal(t, saga.NACKING_DELAY_MS*time.Millisecond, fixed.DelayFor(4))
+
+	exponential := saga.RetryPolicy{Backoff: saga.BackoffExponential, Delay: 100 * time.Millisecond, MaxDelay: time.Second}
+	assert.Equal(t, 100*time.Millisecond, exponential.DelayFor(1))
+	assert.Equal(t, 800*time.Millisecond, exponential.DelayFor(4))
+	assert.Equal(t, time.Second, exponential.DelayFor(5))
+
+	jitter := saga.RetryPolicy{Backoff: saga.BackoffFixed, Delay: time.Second, Jitter: 0.5}
+	for range 100 {
+		delay := jitter.DelayFor(1)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+}
+
+func TestSettleOutcomes(t *testing.T) {
+	cause := errors.New("insufficient funds")
+	tests := []struct {
+		name    string
+		outcome error
+		// retried is whether the message is delivered again, with the occurrence of the fibonacci strategy
+		retried    bool
+		occurrence int32
+		parked     bool
+	}{
+		{name: "ack", outcome: nil},
+		{name: "retry", outcome: saga.Retry(cause, 10*time.Millisecond), retried: true},
+		{name: "retry fibonacci", outcome: saga.RetryFibonacci(cause), retried: true, occurrence: 1},
+		{name: "retry policy", outcome: cause, retried: true},
+		{name: "reject", outcome: saga.Reject(cause), parked: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transactional, emitter := connectTestEvents(t, saga.Opts{
+				Events:      []event.MicroserviceEvent{event.TestImageEvent},
+				RetryPolicy: saga.RetryPolicy{Backoff: saga.BackoffFixed, Delay: 10 * time.Millisecond},
+			})
+			redelivered := make(chan *saga.EventsConsumeChannel, 1)
+			saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, channel *saga.EventsConsumeChannel) error {
+				if channel.Attempt() == 1 {
+					return tt.outcome
+				}
+				redelivered <- channel
+				return nil
+			})
+
+			require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: tt.name}))
+
+			if tt.retried {
+				select {
+				case channel := <-redelivered:
+					assert.EqualValues(t, 2, channel.Attempt())
+					assert.Equal(t, tt.occurrence, channel.Occurrence())
+					assert.Contains(t, channel.LastError(), cause.Error())
+				case <-time.After(10 * time.Second):
+					t.Fatal("the event was not retried")
+				}
+				return
+			}
+
+			filter := saga.ParkedFilter{Handler: string(event.TestImageEvent)}
+			if tt.parked {
+				var (
+					parked []saga.ParkedMessage
+					err    error
+				)
+				require.Eventually(t, func() bool {
+					parked, err = transactional.ParkedMessages(context.Background(), filter)
+					return err == nil && len(parked) == 1
+				}, 10*time.Second, 50*time.Millisecond)
+				assert.Equal(t, "rejected: insufficient funds", parked[0].Reason)
+			}
+			// an acked or parked message is neither redelivered nor, when acked, parked
+			select {
+			case <-redelivered:
+				t.Fatal("the settled event was delivered again")
+			case <-time.After(2 * time.Second):
+			}
+			if !tt.parked {
+				parked, err := transactional.ParkedMessages(context.Background(), filter)
+				require.NoError(t, err)
+				assert.Empty(t, parked)
+			}
+		})
+	}
+}
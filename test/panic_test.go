@@ -0,0 +1,80 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandlerPanic(t *testing.T) {
+	tests := []struct {
+		strategy saga.PanicStrategy
+		parked   bool
+	}{
+		{strategy: saga.PanicParkingLot, parked: true},
+		{strategy: saga.PanicRetryPolicy, parked: false},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.strategy), func(t *testing.T) {
+			transactional, emitter := connectTestEvents(t, saga.Opts{
+				Events:        []event.MicroserviceEvent{event.TestImageEvent},
+				PanicStrategy: tt.strategy,
+				RetryPolicy:   saga.RetryPolicy{Backoff: saga.BackoffFixed, Delay: 10 * time.Millisecond},
+			})
+			deadLetters := auditEvents(t, event.AuditDeadLetterEvent)
+			retried := make(chan string, 1)
+			saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, channel *saga.EventsConsumeChannel) error {
+				if channel.Attempt() == 1 {
+					panic("boom")
+				}
+				retried <- channel.LastError()
+				return nil
+			})
+
+			require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "panic"}))
+
+			if tt.parked {
+				var (
+					parked []saga.ParkedMessage
+					err    error
+				)
+				require.Eventually(t, func() bool {
+					parked, err = transactional.ParkedMessages(context.Background(), saga.ParkedFilter{Handler: string(event.TestImageEvent)})
+					return err == nil && len(parked) == 1
+				}, 10*time.Second, 50*time.Millisecond)
+				assert.Equal(t, "panic: boom", parked[0].Reason)
+			} else {
+				select {
+				case lastErr := <-retried:
+					assert.Equal(t, "panic: boom", lastErr)
+				case <-time.After(10 * time.Second):
+					t.Fatal("the event was not retried after the panic")
+				}
+			}
+
+			queue := string(transactional.Microservice) + "_match_commands"
+			timeout := time.After(10 * time.Second)
+			for {
+				select {
+				case msg := <-deadLetters:
+					var deadLetter event.AuditDeadLetterPayload
+					require.NoError(t, json.Unmarshal(msg.Body, &deadLetter))
+					if deadLetter.QueueName != queue {
+						continue
+					}
+					assert.Equal(t, "panic: boom", deadLetter.RejectionReason)
+					assert.Equal(t, string(event.TestImageEvent), deadLetter.RejectedEvent)
+					return
+				case <-timeout:
+					t.Fatal("the audit.dead_letter event was not published")
+				}
+			}
+		})
+	}
+}
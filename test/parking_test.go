@@ -0,0 +1,152 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParkingLotOnExhaustedRetries(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestMintEvent},
+	})
+	attempts := make(chan int32, saga.MAX_NACK_RETRIES+1)
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestMintPayload, channel *saga.EventsConsumeChannel) error {
+		if channel.Attempt() > 1 {
+			assert.Equal(t, "mint failed", channel.LastError())
+		}
+		attempts <- channel.Attempt()
+		return saga.Retry(errors.New("mint failed"), 10*time.Millisecond)
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestMintPayload{Mint: "exhausted"}))
+	for i := range int32(saga.MAX_NACK_RETRIES + 1) {
+		select {
+		case attempt := <-attempts:
+			assert.Equal(t, i+1, attempt)
+		case <-time.After(10 * time.Second):
+			t.Fatal("the event was not retried")
+		}
+	}
+
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+
+	parkingLot := string(transactional.Microservice) + "_match_commands_parking_lot"
+	var msg amqp.Delivery
+	require.Eventually(t, func() bool {
+		var ok bool
+		msg, ok, err = ch.Get(parkingLot, true)
+		return err == nil && ok
+	}, 5*time.Second, 50*time.Millisecond)
+
+	assert.Equal(t, "mint failed", msg.Headers[saga.LastErrorHeader])
+	assert.EqualValues(t, saga.MAX_NACK_RETRIES+1, msg.Headers[saga.AttemptsHeader])
+	assert.Equal(t, string(event.TestMintEvent), msg.Headers[saga.HandlerHeader])
+	assert.Contains(t, msg.Headers, saga.FirstFailureAtHeader)
+	assert.Contains(t, msg.Headers, saga.LastFailureAtHeader)
+	assert.Equal(t, "max retries reached: 3", msg.Headers[saga.ParkingReasonHeader])
+}
+
+func TestReplayParkedMessages(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+	})
+	ctx := context.Background()
+
+	received := make(chan bool, 2)
+	var deliveries atomic.Int32
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		replayed := deliveries.Add(1) > 1
+		received <- replayed
+		if !replayed {
+			return saga.Reject(errors.New("image not ready"))
+		}
+		return nil
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "parked"}))
+	require.False(t, <-received)
+
+	filter := saga.ParkedFilter{Handler: string(event.TestImageEvent), Error: "image not ready"}
+	var (
+		parked []saga.ParkedMessage
+		err    error
+	)
+	require.Eventually(t, func() bool {
+		parked, err = transactional.ParkedMessages(ctx, filter)
+		return err == nil && len(parked) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, string(transactional.Microservice), parked[0].AppID)
+	assert.Equal(t, string(transactional.Microservice)+"_match_commands", parked[0].Queue)
+
+	shown, err := transactional.ParkedMessage(ctx, parked[0].MessageID)
+	require.NoError(t, err)
+	assert.Equal(t, parked[0].Body, shown.Body)
+	_, err = transactional.ParkedMessage(ctx, "missing")
+	require.ErrorIs(t, err, saga.ErrNotParked)
+
+	n, err := transactional.ReplayParked(ctx, saga.ParkedFilter{MessageIDs: []string{parked[0].MessageID}})
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+	select {
+	case replayed := <-received:
+		require.True(t, replayed)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the parked event was not replayed")
+	}
+
+	n, err = transactional.PurgeParked(ctx, filter)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestReplayRestartsRetries(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestMintEvent},
+		// parked on the second failure
+		RetryPolicy: saga.RetryPolicy{Backoff: saga.BackoffFixed, Delay: 10 * time.Millisecond, MaxAttempts: 1, MaxAge: time.Hour},
+	})
+	ctx := context.Background()
+
+	var replaying atomic.Bool
+	replayed := make(chan bool, 1)
+	saga.OnEvent(emitter, func(_ context.Context, _ event.TestMintPayload, channel *saga.EventsConsumeChannel) error {
+		if !replaying.Load() {
+			return errors.New("mint failed")
+		}
+		_, failed := channel.FirstFailureAt()
+		replayed <- channel.Attempt() == 1 && !failed
+		return nil
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestMintPayload{Mint: "replayed"}))
+	var parked []saga.ParkedMessage
+	require.Eventually(t, func() bool {
+		parked, _ = transactional.ParkedMessages(ctx, saga.ParkedFilter{})
+		return len(parked) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.False(t, parked[0].FirstFailureAt.IsZero())
+
+	replaying.Store(true)
+	n, err := transactional.ReplayParked(ctx, saga.ParkedFilter{})
+	require.NoError(t, err)
+	require.Equal(t, 1, n)
+	select {
+	case restarted := <-replayed:
+		assert.True(t, restarted, "the replayed message keeps its retry count or its first failure")
+	case <-time.After(10 * time.Second):
+		t.Fatal("the parked event was not replayed")
+	}
+}
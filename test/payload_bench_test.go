@@ -0,0 +1,40 @@
+package test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+)
+
+var scoreSubmittedBody = []byte(`{"eventId":9007199254740993,"userId":"64f7d1c3b0d8f1b2a4c6a123","score":1250.5,"totalScore":98231.25,"matchId":"match-42","submittedAt":"2025-01-01T00:00:00Z"}`)
+
+// BenchmarkDecodePayload compares decoding a legend_events.score_submitted body through the
+// Payload map, the previous path, with decoding it straight from the raw body.
+//
+//	go test -run ^$ -bench BenchmarkDecodePayload -benchmem ./test/...
+func BenchmarkDecodePayload(b *testing.B) {
+	b.Run("map", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			var payload map[string]interface{}
+			if err := json.Unmarshal(scoreSubmittedBody, &payload); err != nil {
+				b.Fatal(err)
+			}
+			if _, err := saga.DecodePayload[event.LegendEventsScoreSubmittedPayload](payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("raw", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			handler := saga.EventHandler{Raw: scoreSubmittedBody}
+			var payload event.LegendEventsScoreSubmittedPayload
+			if err := handler.Decode(&payload); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
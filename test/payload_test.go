@@ -0,0 +1,106 @@
+package test
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/legendaryum-metaverse/saga/micro"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodePayload(t *testing.T) {
+	payload, err := saga.DecodePayload[event.SocialBlockChatPayload](map[string]interface{}{
+		"userId":        "1234",
+		"userToBlockId": "4321",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1234", payload.UserID)
+	assert.Equal(t, "4321", payload.UserToBlockID)
+
+	_, err = saga.DecodePayload[event.SocialBlockChatPayload](map[string]interface{}{
+		"userId": 1234,
+	})
+	require.ErrorIs(t, err, saga.ErrInvalidPayload)
+	assert.Panics(t, func() {
+		saga.ParsePayload(map[string]interface{}{"userId": 1234}, &event.SocialBlockChatPayload{})
+	})
+}
+
+func TestCommandRegistry(t *testing.T) {
+	spec, ok := micro.LookupCommand(micro.TransferMissionRewardToWinner)
+	require.True(t, ok)
+	assert.Equal(t, reflect.TypeFor[micro.TransferMissionRewardToWinnerPayload](), spec.Payload)
+	assert.Equal(t, reflect.TypeFor[micro.NoResult](), spec.Result)
+
+	for _, cmd := range []micro.StepCommand{
+		micro.CreateImageCommand, micro.UpdateTokenCommand, micro.MintImageCommand, micro.CreateUserCommand,
+		micro.TransferMissionRewardToWinner, micro.TransferRewardToWinners,
+		micro.UpdateUserImageCommand, micro.CreateSocialUserCommand, micro.UploadFileCommand,
+	} {
+		_, ok = micro.LookupCommand(cmd)
+		assert.True(t, ok, "command %s is not registered", cmd)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	_, err := saga.DecodePayload[event.BillingPaymentSucceededPayload](map[string]interface{}{
+		"userId":     "1234",
+		"amount":     100,
+		"currency":   "usdt",
+		"occurredAt": "2025-01-01T00:00:00Z",
+	})
+	require.ErrorIs(t, err, saga.ErrInvalidPayload)
+	var validationErr *saga.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []saga.FieldError{
+		{Field: "paymentId", Tag: "required"},
+		{Field: "currency", Tag: "len", Param: "3"},
+	}, validationErr.Fields)
+}
+
+func TestCommenceSagaValidation(t *testing.T) {
+	transactional := newTestTransactional(t, saga.Opts{})
+	sagas := commencedSagas(t)
+
+	invalid := saga.TransferCryptoRewardToMissionWinnerPayload{UserID: "1234"}
+	err := transactional.CommenceSaga(invalid)
+	require.ErrorIs(t, err, saga.ErrInvalidPayload)
+	var validationErr *saga.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, []saga.FieldError{
+		{Field: "walletAddress", Tag: "required"},
+		{Field: "reward", Tag: "required"},
+	}, validationErr.Fields)
+	assert.Empty(t, sagas, "the invalid saga was sent")
+
+	require.NoError(t, transactional.CommenceSaga(invalid, saga.SkipValidation()))
+	select {
+	case msg := <-sagas:
+		var commenced struct {
+			Title   saga.SagaTitle                                  `json:"title"`
+			Payload saga.TransferCryptoRewardToMissionWinnerPayload `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(msg.Body, &commenced))
+		assert.Equal(t, saga.TransferCryptoRewardToMissionWinner, commenced.Title)
+		assert.Equal(t, invalid, commenced.Payload)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the saga was not commenced with SkipValidation")
+	}
+}
+
+func TestDecodeRaw(t *testing.T) {
+	handler := saga.EventHandler{Raw: scoreSubmittedBody}
+	var payload event.LegendEventsScoreSubmittedPayload
+	require.NoError(t, handler.Decode(&payload))
+	// large integers keep their precision, they are not decoded as float64
+	assert.Equal(t, 9007199254740993, payload.EventID)
+
+	handler = saga.EventHandler{Raw: []byte(`{"eventId":1}`)}
+	var invalid event.LegendEventsScoreSubmittedPayload
+	require.ErrorIs(t, handler.Decode(&invalid), saga.ErrInvalidPayload)
+}
@@ -1,6 +1,7 @@
 package test
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -36,6 +37,34 @@ func (suite *EventsTestSuite) TestHealthCheck() {
 	suite.Require().NoError(err)
 }
 
+func (suite *EventsTestSuite) TestStatus() {
+	suite.Equal(saga.StatusConnected, suite.t.Status())
+}
+
+func (suite *EventsTestSuite) TestUnroutableEvent() {
+	// no microservice is subscribed to test.mint
+	err := suite.t.PublishEvent(&event.TestMintPayload{Mint: "mint"})
+	suite.Require().ErrorIs(err, saga.ErrUnroutable)
+}
+
+func (suite *EventsTestSuite) TestInvalidEventPayload() {
+	err := suite.t.PublishEvent(&event.BillingPaymentSucceededPayload{UserID: "1234"})
+	suite.Require().ErrorIs(err, saga.ErrInvalidPayload)
+	var validationErr *saga.ValidationError
+	suite.Require().ErrorAs(err, &validationErr)
+	suite.Len(validationErr.Fields, 4)
+
+	err = suite.t.PublishEvent(&event.TestMintPayload{}, saga.SkipValidation())
+	suite.Require().ErrorIs(err, saga.ErrUnroutable)
+}
+
+func (suite *EventsTestSuite) TestPublishEventContext() {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := suite.t.PublishEventContext(ctx, &event.TestMintPayload{Mint: "mint"})
+	suite.Require().ErrorIs(err, context.Canceled)
+}
+
 func stringPtr(s string) *string {
 	return &s
 }
@@ -0,0 +1,114 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/require"
+)
+
+// BenchmarkPublishEvent compares pools of send channels of different sizes under concurrent
+// publishers. channels=1 is the pool with a single send channel: the publishes are serialized on
+// it, one confirmation at a time. baseline is the publisher the pool replaced: every publisher
+// shares a single channel without confirmations, and the messages are not mandatory.
+//
+//	GO_ENV=test go test -run ^$ -bench BenchmarkPublishEvent ./test/...
+func BenchmarkPublishEvent(b *testing.B) {
+	b.Run("baseline", func(b *testing.B) {
+		transactional, e := connectTestEvents(b, saga.Opts{
+			Events: []event.MicroserviceEvent{event.TestImageEvent},
+		})
+		e.On(event.TestImageEvent, func(handler saga.EventHandler) {
+			handler.Channel.AckMessage()
+		})
+		conn, err := amqp.Dial(rabbitURI)
+		require.NoError(b, err)
+		defer conn.Close()
+		ch, err := conn.Channel()
+		require.NoError(b, err)
+
+		b.SetParallelism(4)
+		b.ResetTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if err := publishUnconfirmed(ch, string(transactional.Microservice), &event.TestImagePayload{Image: "image"}); err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	})
+
+	for _, channels := range []int{1, saga.DefaultPublisherChannels} {
+		b.Run(fmt.Sprintf("channels=%d", channels), func(b *testing.B) {
+			// the subscription makes the event routable and drains the queue
+			transactional, e := connectTestEvents(b, saga.Opts{
+				Events:            []event.MicroserviceEvent{event.TestImageEvent},
+				PublisherChannels: channels,
+			})
+			e.On(event.TestImageEvent, func(handler saga.EventHandler) {
+				handler.Channel.AckMessage()
+			})
+
+			b.SetParallelism(4)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if err := transactional.PublishEvent(&event.TestImagePayload{Image: "image"}); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// publishUnconfirmed publishes the event and its audit.published event like the single channel
+// publisher did, without waiting for the broker.
+func publishUnconfirmed(ch *amqp.Channel, microservice string, payload event.PayloadEvent) error {
+	eventID := uuid.Must(uuid.NewV7()).String()
+	headers := amqp.Table{
+		"all-micro":                         "yes",
+		saga.EventHeaderKey(payload.Type()): string(payload.Type()),
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = ch.PublishWithContext(ctx, string(saga.MatchingExchange), "", false, false, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  saga.ContentTypeJSON,
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    eventID,
+		AppId:        microservice,
+	})
+	if err != nil {
+		return err
+	}
+
+	audit := event.AuditPublishedPayload{
+		PublisherMicroservice: microservice,
+		PublishedEvent:        string(payload.Type()),
+		PublishedAt:           uint64(time.Now().UnixMilli()),
+		EventID:               eventID,
+	}
+	body, err = json.Marshal(audit)
+	if err != nil {
+		return err
+	}
+	return ch.PublishWithContext(ctx, string(saga.AuditExchange), string(audit.Type()), false, false, amqp.Publishing{
+		ContentType:  saga.ContentTypeJSON,
+		Body:         body,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    uuid.Must(uuid.NewV7()).String(),
+	})
+}
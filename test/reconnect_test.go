@@ -0,0 +1,135 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConsumerResumesAfterCancel(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+	})
+	received := make(chan event.TestImagePayload, 16)
+	saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		received <- p
+		return nil
+	})
+
+	// deleting the consumer queue makes the broker cancel the consumer
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	_, err = ch.QueueDelete(string(transactional.Microservice)+"_match_commands", false, false, false)
+	require.NoError(t, err)
+
+	// the events are unroutable until the queue is declared again by the resumed consumer
+	assert.Eventually(t, func() bool {
+		_ = transactional.PublishEvent(&event.TestImagePayload{Image: "resumed"})
+		select {
+		case p := <-received:
+			return p.Image == "resumed"
+		case <-time.After(500 * time.Millisecond):
+			return false
+		}
+	}, 20*time.Second, 100*time.Millisecond)
+	assert.Equal(t, saga.StatusConnected, transactional.Status())
+}
+
+// managementAPI is the RabbitMQ management API of the docker compose broker.
+const managementAPI = "http://localhost:15672/api"
+
+// closeConsumerConnection force closes, through the management API, the connection of the consumer
+// whose tag starts with the prefix.
+func closeConsumerConnection(t *testing.T, tagPrefix string) {
+	t.Helper()
+	var connection string
+	require.Eventually(t, func() bool {
+		req, err := http.NewRequest(http.MethodGet, managementAPI+"/consumers", nil)
+		require.NoError(t, err)
+		req.SetBasicAuth("rabbit", "1234")
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+		var consumers []struct {
+			ConsumerTag    string `json:"consumer_tag"`
+			ChannelDetails struct {
+				ConnectionName string `json:"connection_name"`
+			} `json:"channel_details"`
+		}
+		require.NoError(t, json.NewDecoder(res.Body).Decode(&consumers))
+		for _, c := range consumers {
+			if strings.HasPrefix(c.ConsumerTag, tagPrefix) {
+				connection = c.ChannelDetails.ConnectionName
+				return true
+			}
+		}
+		// the management API lists the consumers with some delay
+		return false
+	}, 20*time.Second, 500*time.Millisecond)
+
+	req, err := http.NewRequest(http.MethodDelete, managementAPI+"/connections/"+url.PathEscape(connection), nil)
+	require.NoError(t, err)
+	req.SetBasicAuth("rabbit", "1234")
+	req.Header.Set("X-Reason", "closed by the test")
+	res, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer res.Body.Close()
+	require.Equal(t, http.StatusNoContent, res.StatusCode)
+}
+
+func TestReconnectAfterConnectionClosed(t *testing.T) {
+	statuses := make(chan saga.ConnectionStatus, 64)
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events: []event.MicroserviceEvent{event.TestImageEvent},
+		OnReconnect: func(ev saga.ReconnectEvent) {
+			statuses <- ev.Status
+		},
+	})
+	received := make(chan event.TestImagePayload, 16)
+	saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		received <- p
+		return nil
+	})
+
+	closeConsumerConnection(t, string(transactional.Microservice)+"_events_")
+
+	// the status goes through disconnected and back to connected, with the attempts in between
+	var seen []saga.ConnectionStatus
+	require.Eventually(t, func() bool {
+		for {
+			select {
+			case status := <-statuses:
+				seen = append(seen, status)
+			default:
+				return len(seen) > 0 && seen[len(seen)-1] == saga.StatusConnected
+			}
+		}
+	}, 30*time.Second, 100*time.Millisecond)
+	assert.Equal(t, saga.StatusDisconnected, seen[0], "statuses %v", seen)
+	assert.Contains(t, seen, saga.StatusReconnecting, "statuses %v", seen)
+	assert.Equal(t, saga.StatusConnected, transactional.Status())
+	assert.NoError(t, transactional.HealthCheck())
+
+	// the consumer is resumed on the new connection
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "reconnected"}))
+	select {
+	case p := <-received:
+		assert.Equal(t, "reconnected", p.Image)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the consumer was not resumed after the reconnection")
+	}
+}
@@ -0,0 +1,99 @@
+package test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelayBucket(t *testing.T) {
+	for delay, bucket := range map[time.Duration]time.Duration{
+		0:                      time.Second,
+		10 * time.Millisecond:  time.Second,
+		2 * time.Second:        time.Second,
+		3 * time.Second:        5 * time.Second,
+		5 * time.Second:        5 * time.Second,
+		13 * time.Second:       30 * time.Second,
+		8 * time.Minute:        10 * time.Minute,
+		2 * time.Hour:          time.Hour,
+		3 * time.Hour:          6 * time.Hour,
+		48 * time.Hour:         6 * time.Hour,
+		time.Duration(1 << 62): 6 * time.Hour,
+	} {
+		assert.Equal(t, bucket, saga.DelayBucket(nil, delay), "delay %s", delay)
+	}
+	assert.Equal(t, 100*time.Millisecond, saga.DelayBucket([]time.Duration{100 * time.Millisecond, time.Second}, 0))
+}
+
+func TestDelayBucketDropsJitter(t *testing.T) {
+	// the first fibonacci delays are below the gap between the first two buckets
+	fibonacci := saga.RetryPolicy{}
+	for attempt := int32(1); attempt <= 3; attempt++ {
+		assert.Equal(t, time.Second, saga.DelayBucket(nil, fibonacci.DelayFor(attempt)), "attempt %d", attempt)
+	}
+
+	jitter := saga.RetryPolicy{Backoff: saga.BackoffFixed, Delay: time.Second, Jitter: 0.5}
+	for range 100 {
+		delay := jitter.DelayFor(1)
+		assert.Equal(t, time.Second, saga.DelayBucket(nil, delay), "delay %s", delay)
+	}
+}
+
+// requeueOrder publishes an event retried after 5 seconds and then one retried right away, and returns
+// the payloads in the order they are redelivered.
+func requeueOrder(t *testing.T, requeue saga.RequeueStrategy) []string {
+	transactional := newTestTransactional(t, saga.Opts{
+		Events:  []event.MicroserviceEvent{event.TestMintEvent},
+		Requeue: requeue,
+	})
+	emitter, err := transactional.ConnectEvents()
+	if requeue == saga.RequeueDelayedExchange && errors.Is(err, saga.ErrTopology) {
+		t.Skip("the rabbitmq_delayed_message_exchange plugin is not enabled")
+	}
+	require.NoError(t, err)
+	redelivered := make(chan string, 2)
+	saga.OnEvent(emitter, func(_ context.Context, payload event.TestMintPayload, channel *saga.EventsConsumeChannel) error {
+		if channel.Attempt() > 1 {
+			redelivered <- payload.Mint
+			return nil
+		}
+		delay := 5 * time.Second
+		if payload.Mint == "short" {
+			delay = 10 * time.Millisecond
+		}
+		return saga.Retry(errors.New("not yet"), delay)
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestMintPayload{Mint: "long"}))
+	require.NoError(t, transactional.PublishEvent(&event.TestMintPayload{Mint: "short"}))
+
+	var order []string
+	for range 2 {
+		select {
+		case mint := <-redelivered:
+			order = append(order, mint)
+		case <-time.After(15 * time.Second):
+			t.Fatalf("the events were not redelivered, got %v", order)
+		}
+	}
+	return order
+}
+
+func TestShortDelayNotBlockedByLongDelay(t *testing.T) {
+	assert.Equal(t, []string{"short", "long"}, requeueOrder(t, saga.RequeueDelayBuckets))
+}
+
+func TestShortDelayNotBlockedByLongDelayedExchange(t *testing.T) {
+	assert.Equal(t, []string{"short", "long"}, requeueOrder(t, saga.RequeueDelayedExchange))
+}
+
+func TestShortDelayBlockedByLongExpiration(t *testing.T) {
+	// RabbitMQ only expires the message at the head of the requeue queue
+	assert.Equal(t, []string{"long", "short"}, requeueOrder(t, saga.RequeueExpiration))
+}
@@ -0,0 +1,110 @@
+package test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/legendaryum-metaverse/saga"
+	"github.com/legendaryum-metaverse/saga/event"
+	amqp "github.com/rabbitmq/amqp091-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnhandledWaitsWithoutBlocking(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events:             []event.MicroserviceEvent{event.TestImageEvent, event.TestMintEvent},
+		HandlerGracePeriod: time.Hour,
+		// the waiting image holds a prefetch slot, the mint needs another one
+		Prefetch: 2,
+	})
+	minted := make(chan string, 1)
+	saga.OnEvent(emitter, func(_ context.Context, p event.TestMintPayload, _ *saga.EventsConsumeChannel) error {
+		minted <- p.Mint
+		return nil
+	})
+
+	// the image waits for its handler during the grace period, the mint is handled meanwhile
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "unhandled"}))
+	require.NoError(t, transactional.PublishEvent(&event.TestMintPayload{Mint: "handled"}))
+	select {
+	case mint := <-minted:
+		assert.Equal(t, "handled", mint)
+	case <-time.After(10 * time.Second):
+		t.Fatal("the event with handler was held up by the one without")
+	}
+
+	filter := saga.ParkedFilter{Handler: string(event.TestImageEvent)}
+	parked, err := transactional.ParkedMessages(context.Background(), filter)
+	require.NoError(t, err)
+	assert.Empty(t, parked)
+
+	// Run ends the grace period, the image is parked
+	ctx, cancel := context.WithCancel(context.Background())
+	run := make(chan error, 1)
+	go func() {
+		run <- transactional.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		assert.NoError(t, <-run)
+	}()
+
+	require.Eventually(t, func() bool {
+		parked, err = transactional.ParkedMessages(context.Background(), filter)
+		return err == nil && len(parked) == 1
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Equal(t, "unhandled: no handler registered for test.image", parked[0].Reason)
+}
+
+func TestHandlerRegisteredDuringGracePeriod(t *testing.T) {
+	transactional, emitter := connectTestEvents(t, saga.Opts{
+		Events:             []event.MicroserviceEvent{event.TestImageEvent},
+		HandlerGracePeriod: 10 * time.Second,
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "late"}))
+	time.Sleep(300 * time.Millisecond)
+
+	received := make(chan string, 1)
+	saga.OnEvent(emitter, func(_ context.Context, p event.TestImagePayload, _ *saga.EventsConsumeChannel) error {
+		received <- p.Image
+		return nil
+	})
+	select {
+	case image := <-received:
+		assert.Equal(t, "late", image)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the event was not handed to the handler registered during the grace period")
+	}
+}
+
+func TestUnhandledAck(t *testing.T) {
+	transactional, _ := connectTestEvents(t, saga.Opts{
+		Events:             []event.MicroserviceEvent{event.TestImageEvent},
+		Unhandled:          saga.UnhandledAck,
+		HandlerGracePeriod: 10 * time.Millisecond,
+	})
+
+	require.NoError(t, transactional.PublishEvent(&event.TestImagePayload{Image: "acked"}))
+	time.Sleep(time.Second)
+	// an unacked message would be back in the queue once the channel is closed
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, transactional.Shutdown(ctx))
+
+	conn, err := amqp.Dial(rabbitURI)
+	require.NoError(t, err)
+	defer conn.Close()
+	ch, err := conn.Channel()
+	require.NoError(t, err)
+	defer ch.Close()
+	queue := string(transactional.Microservice) + "_match_commands"
+	for _, q := range []string{queue, queue + "_parking_lot"} {
+		// the parking lot queue is declared with the consumer queue
+		info, err := ch.QueueDeclarePassive(q, true, false, false, false, nil)
+		require.NoError(t, err)
+		assert.Zero(t, info.Messages, q)
+	}
+}
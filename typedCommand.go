@@ -0,0 +1,82 @@
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"reflect"
+
+	"github.com/legendaryum-metaverse/saga/micro"
+)
+
+// CommandHandlerFunc handles the payload of a saga command registered with OnCommand, the result is
+// passed to the next step of the saga.
+type CommandHandlerFunc[P, R any] func(ctx context.Context, payload P, ch *MicroserviceConsumeChannel) (R, error)
+
+// OnCommand registers a typed handler of the command, the PreviousPayload of the saga step is decoded
+// into P and validated, and the returned R becomes the NextStepPayload of the AckMessage.
+//
+// A payload that cannot be decoded or does not pass the validation never reaches the handler, it is
+// stored in the parking lot queue of the consumer. The error returned by the handler acks or nacks the
+// message, see Retry, RetryFibonacci and Reject.
+//
+//	saga.OnCommand(emitter, micro.TransferMissionRewardToWinnerStep,
+//		func(ctx context.Context, p micro.TransferMissionRewardToWinnerPayload, ch *saga.MicroserviceConsumeChannel) (micro.NoResult, error) {
+//			return micro.NoResult{}, transfer(ctx, p.WalletAddress, p.UserID, p.Reward)
+//		})
+func OnCommand[P, R any](e *Emitter[CommandHandler, micro.StepCommand], cmd micro.Command[P, R], handler CommandHandlerFunc[P, R]) {
+	e.register(cmd.Name, func(h CommandHandler) {
+		var payload P
+		if err := decodeStepPayload(h.Raw, &payload); err != nil {
+			if rejectErr := h.Channel.park("rejected: " + err.Error()); rejectErr != nil {
+				log.Printf("Error rejecting invalid payload of %s: %v", cmd.Name, rejectErr)
+			}
+			return
+		}
+		result, err := handler(h.Context, payload, h.Channel)
+		if err != nil {
+			h.Channel.settleWith(err, nil)
+			return
+		}
+		next, err := encodeStepPayload(result)
+		if err != nil {
+			// the result cannot be passed to the next step, retrying would not help
+			h.Channel.settleWith(Reject(err), nil)
+			return
+		}
+		h.Channel.settleWith(nil, next)
+	}, false)
+}
+
+// decodeStepPayload decodes the PreviousPayload of a saga step into data and validates it when it is a struct.
+// The error is an *Error matching ErrInvalidPayload.
+func decodeStepPayload(raw json.RawMessage, data any) error {
+	if len(raw) == 0 {
+		raw = json.RawMessage("null")
+	}
+	if err := json.Unmarshal(raw, data); err != nil {
+		return newError(ErrInvalidPayload, "failed to unmarshal payload", err)
+	}
+	if rv := reflect.Indirect(reflect.ValueOf(data)); rv.Kind() == reflect.Struct {
+		if err := validatePayload(data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeStepPayload turns the result of a command handler into the payload of the next step.
+func encodeStepPayload(result any) (NextStepPayload, error) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, newError(ErrInvalidPayload, "failed to marshal result", err)
+	}
+	next := NextStepPayload{}
+	if string(body) == "null" {
+		return next, nil
+	}
+	if err = json.Unmarshal(body, &next); err != nil {
+		return nil, newError(ErrInvalidPayload, "result is not a JSON object", err)
+	}
+	return next, nil
+}
@@ -0,0 +1,44 @@
+package saga
+
+import (
+	"context"
+	"log"
+	"reflect"
+
+	"github.com/legendaryum-metaverse/saga/event"
+)
+
+// EventHandlerFunc handles the payload of an event registered with OnEvent.
+type EventHandlerFunc[T event.PayloadEvent] func(ctx context.Context, payload T, ch *EventsConsumeChannel) error
+
+// OnEvent registers a typed handler of the event T.Type(), the payload is decoded straight from the
+// delivery body and validated. T is the payload type, e.g. event.SocialNewUserPayload.
+//
+// A payload that cannot be decoded or does not pass the validation never reaches the handler, it is
+// stored in the parking lot queue of the consumer and an audit.dead_letter event is emitted.
+// The error returned by the handler acks or nacks the message, see Retry, RetryFibonacci and Reject.
+//
+//	saga.OnEvent(emitter, func(ctx context.Context, p event.SocialNewUserPayload, ch *saga.EventsConsumeChannel) error {
+//		return createUser(ctx, p.SocialUser)
+//	})
+func OnEvent[T event.PayloadEvent](e *Emitter[EventHandler, event.MicroserviceEvent], handler EventHandlerFunc[T]) {
+	e.register(newPayload[T]().Type(), func(h EventHandler) {
+		payload := newPayload[T]()
+		if err := h.Decode(&payload); err != nil {
+			if rejectErr := h.Channel.reject(err); rejectErr != nil {
+				log.Printf("Error rejecting invalid payload of %s: %v", h.Channel.eventType, rejectErr)
+			}
+			return
+		}
+		h.Channel.settleWith(handler(h.Context, payload, h.Channel))
+	}, false)
+}
+
+// newPayload returns the zero value of T, allocated when T is a pointer so that its Type method can be called.
+func newPayload[T any]() T {
+	var payload T
+	if rt := reflect.TypeFor[T](); rt.Kind() == reflect.Pointer {
+		payload = reflect.New(rt.Elem()).Interface().(T)
+	}
+	return payload
+}
@@ -0,0 +1,84 @@
+package saga
+
+import (
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UnhandledPolicy decides what happens to a message whose event or saga command has no handler
+// registered with On, and no fallback handler registered with OnUnhandled.
+type UnhandledPolicy string
+
+const (
+	// UnhandledParkingLot stores the message in the parking lot queue of the consumer, "<queue>_parking_lot".
+	// It is the default policy.
+	UnhandledParkingLot UnhandledPolicy = "parking_lot"
+	// UnhandledAck acks the message and logs it.
+	UnhandledAck UnhandledPolicy = "ack"
+)
+
+// DefaultHandlerGracePeriod is the time a message waits for its handler to be registered after
+// connecting, the handlers are usually registered right after ConnectToEvents returns.
+const DefaultHandlerGracePeriod = 10 * time.Second
+
+func (c *ConsumeChannel) unhandled(key string) error {
+	if c.t.unhandled == UnhandledAck {
+		log.Printf("No handler registered for %s in %s, acking the message", key, c.queueName)
+		defer c.settle()
+		err := c.channel.Ack(c.msg.DeliveryTag, false)
+		if err != nil {
+			return fmt.Errorf("error acknowledging message: %w", err)
+		}
+		return nil
+	}
+	log.Printf("No handler registered for %s in %s, sending the message to %s", key, c.queueName, getParkingLotQueue(c.queueName))
+	return c.park(fmt.Sprintf("unhandled: no handler registered for %s", key))
+}
+
+// unhandled applies the UnhandledPolicy and emits an audit.dead_letter event when the event is parked.
+func (m *EventsConsumeChannel) unhandled() error {
+	err := m.ConsumeChannel.unhandled(m.eventType)
+	if err != nil || m.t.unhandled == UnhandledAck {
+		return err
+	}
+	m.publishDeadLetter("unhandled", nil)
+	return nil
+}
+
+// CheckHandlers returns an error matching ErrMissingHandlers if any of the subscribed events,
+// Opts.Events, has no handler registered in the events emitter.
+func (t *Transactional) CheckHandlers() error {
+	t.mu.Lock()
+	e := t.eventsEmitter
+	t.mu.Unlock()
+	if e == nil {
+		return nil
+	}
+
+	var missing []string
+	for _, ev := range t.Events {
+		if !e.handles(ev) {
+			missing = append(missing, string(ev))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	slices.Sort(missing)
+	return newError(ErrMissingHandlers, "subscribed events without handler", fmt.Errorf("%s", strings.Join(missing, ", ")))
+}
+
+// warnMissingHandlers logs the subscribed events without handler once the grace period is over.
+func (t *Transactional) warnMissingHandlers() {
+	time.AfterFunc(t.handlerGracePeriod, func() {
+		if t.isClosing() {
+			return
+		}
+		if err := t.CheckHandlers(); err != nil {
+			log.Printf("Warning: %v, their messages are handled with the %q policy", err, t.unhandled)
+		}
+	})
+}
@@ -0,0 +1,94 @@
+package saga
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError describes a field of a payload that does not pass the validation.
+type FieldError struct {
+	// Field is the JSON path of the field, e.g. "socialUser.email".
+	Field string
+	// Tag is the failing validation, e.g. "required" or "email".
+	Tag string
+	// Param is the parameter of the validation, e.g. "3" for "len=3".
+	Param string
+}
+
+func (f FieldError) String() string {
+	if f.Param != "" {
+		return fmt.Sprintf("%s (%s=%s)", f.Field, f.Tag, f.Param)
+	}
+	return fmt.Sprintf("%s (%s)", f.Field, f.Tag)
+}
+
+// ValidationError lists the fields of a payload that do not pass the validation, the *Error wrapping it
+// matches ErrInvalidPayload.
+type ValidationError struct {
+	// Payload is the name of the payload type.
+	Payload string
+	Fields  []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, len(e.Fields))
+	for i, f := range e.Fields {
+		fields[i] = f.String()
+	}
+	return fmt.Sprintf("%s has invalid fields: %s", e.Payload, strings.Join(fields, ", "))
+}
+
+// PublishOption configures PublishEvent and CommenceSaga.
+type PublishOption func(*publishOptions)
+
+type publishOptions struct {
+	skipValidation bool
+}
+
+// SkipValidation publishes the payload without validating it, for the hot paths where the payload is
+// known to be valid.
+func SkipValidation() PublishOption {
+	return func(o *publishOptions) {
+		o.skipValidation = true
+	}
+}
+
+func newPublishOptions(opts []PublishOption) publishOptions {
+	var o publishOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// validatePayload validates the struct tags of the payload, the error is an *Error matching ErrInvalidPayload
+// that wraps a *ValidationError when some fields do not pass the validation.
+func validatePayload(payload any) error {
+	rv := reflect.ValueOf(payload)
+	for rv.Kind() == reflect.Pointer && !rv.IsNil() && rv.Elem().Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+	err := validate.Struct(rv.Interface())
+	if err == nil {
+		return nil
+	}
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return newError(ErrInvalidPayload, "invalid payload", err)
+	}
+
+	validationErr := &ValidationError{Payload: reflect.Indirect(rv).Type().String()}
+	for _, fe := range fieldErrs {
+		// the namespace starts with the name of the payload type
+		field := fe.Namespace()
+		if _, path, ok := strings.Cut(field, "."); ok {
+			field = path
+		}
+		validationErr.Fields = append(validationErr.Fields, FieldError{Field: field, Tag: fe.Tag(), Param: fe.Param()})
+	}
+	return newError(ErrInvalidPayload, "invalid payload", validationErr)
+}